@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrNoCredential indicates the request did not present the kind of
+// credential an Authenticator recognizes (e.g. no Authorization header for
+// a token-based Authenticator, or no peer certificate for MTLSAuth), as
+// opposed to presenting one that is invalid. AuthMiddleware uses this to
+// fall through to the next configured Authenticator rather than rejecting
+// the request outright.
+var ErrNoCredential = errors.New("no credential presented")
+
+// Identity is the result of a successful Authenticate call: the scopes
+// granted to the request and, for credential types that carry one, the
+// machine identity of the caller (a JWT watcher's machine_id or an mTLS
+// client certificate's CN/SAN).
+type Identity struct {
+	Scopes    []string
+	MachineID string
+}
+
+// Authenticator validates one kind of credential presented on a request and
+// returns the resulting Identity. StaticTokenAuth, JWTAuth, and MTLSAuth are
+// the built-in implementations; AuthMiddleware tries each configured
+// Authenticator in order until one succeeds.
+type Authenticator interface {
+	Authenticate(c *gin.Context) (Identity, error)
+}
+
+// bearerToken extracts the token from a "Bearer <token>" Authorization
+// header, used by both StaticTokenAuth and JWTAuth. Returns ok=false if the
+// header is absent or not in Bearer form, which callers surface as
+// ErrNoCredential.
+func bearerToken(c *gin.Context) (token string, ok bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return "", false
+	}
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", false
+	}
+	return parts[1], true
+}
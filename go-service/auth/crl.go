@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// CRLChecker polls a certificate revocation list file on an interval and
+// answers whether a given certificate serial number has been revoked, for
+// use by MTLSAuth. A nil *CRLChecker (no CRL configured) treats every
+// certificate as non-revoked.
+type CRLChecker struct {
+	path     string
+	interval time.Duration
+
+	mu      sync.RWMutex
+	revoked map[string]bool
+}
+
+// NewCRLChecker creates a CRLChecker that polls the CRL at path every
+// interval. Call Run in its own goroutine to start polling.
+func NewCRLChecker(path string, interval time.Duration) *CRLChecker {
+	return &CRLChecker{path: path, interval: interval, revoked: make(map[string]bool)}
+}
+
+// Run polls the CRL file on a ticker until ctx is canceled, refreshing the
+// revoked-serial set after each poll. Intended to be run in its own
+// goroutine; a failed read or parse leaves the previous set in place.
+func (c *CRLChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.refresh()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refresh()
+		}
+	}
+}
+
+func (c *CRLChecker) refresh() {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		return
+	}
+
+	revoked := make(map[string]bool, len(crl.RevokedCertificateEntries))
+	for _, entry := range crl.RevokedCertificateEntries {
+		revoked[entry.SerialNumber.String()] = true
+	}
+
+	c.mu.Lock()
+	c.revoked = revoked
+	c.mu.Unlock()
+}
+
+// IsRevoked reports whether serial appears on the most recently polled CRL.
+func (c *CRLChecker) IsRevoked(serial *big.Int) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.revoked[serial.String()]
+}
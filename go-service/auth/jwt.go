@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrTokenExpired indicates a JWT parsed successfully but its exp claim has
+// passed.
+var ErrTokenExpired = errors.New("token is expired")
+
+// ErrTokenInvalid indicates a JWT failed signature verification or is
+// otherwise malformed.
+var ErrTokenInvalid = errors.New("token is invalid")
+
+// JWTConfig holds the keys AuthMiddleware and the watcher handlers use to
+// sign and verify JWTs. Secret is used for HS256; RSAPublicKey, if set,
+// additionally allows verifying RS256 tokens issued by an external signer.
+type JWTConfig struct {
+	Secret        string
+	RSAPublicKey  *rsa.PublicKey
+	Expiry        time.Duration
+	RefreshWindow time.Duration
+}
+
+// Claims is the JWT payload issued by POST /v1/watchers/login.
+type Claims struct {
+	MachineID string `json:"machine_id"`
+	jwt.RegisteredClaims
+}
+
+// GenerateJWT issues a new HS256 JWT for machineID, valid for cfg.Expiry.
+func GenerateJWT(cfg *JWTConfig, machineID string) (tokenString string, expiresAt time.Time, err error) {
+	expiresAt = time.Now().UTC().Add(cfg.Expiry)
+
+	claims := Claims{
+		MachineID: machineID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err = token.SignedString([]byte(cfg.Secret))
+	return tokenString, expiresAt, err
+}
+
+// ParseJWT verifies tokenString's signature (HS256 against cfg.Secret, or
+// RS256 against cfg.RSAPublicKey if configured) and returns its claims.
+// Returns ErrTokenExpired or ErrTokenInvalid for the respective failures.
+func ParseJWT(cfg *JWTConfig, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return []byte(cfg.Secret), nil
+		case *jwt.SigningMethodRSA:
+			if cfg.RSAPublicKey == nil {
+				return nil, ErrTokenInvalid
+			}
+			return cfg.RSAPublicKey, nil
+		default:
+			return nil, ErrTokenInvalid
+		}
+	})
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+
+	if !token.Valid {
+		return nil, ErrTokenInvalid
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWTAuth authenticates requests bearing a JWT issued by
+// POST /v1/watchers/login, populating Identity.MachineID from its claims.
+type JWTAuth struct {
+	Config *JWTConfig
+}
+
+// NewJWTAuth creates a JWTAuth that verifies tokens against cfg.
+func NewJWTAuth(cfg *JWTConfig) *JWTAuth {
+	return &JWTAuth{Config: cfg}
+}
+
+// Authenticate implements Authenticator. It returns ErrNoCredential for a
+// token that isn't JWT-shaped (no Authorization header, or not three
+// dot-separated segments), so a chained StaticTokenAuth can still handle a
+// scoped API token in "both" mode.
+func (a *JWTAuth) Authenticate(c *gin.Context) (Identity, error) {
+	token, ok := bearerToken(c)
+	if !ok || !looksLikeJWT(token) {
+		return Identity{}, ErrNoCredential
+	}
+
+	claims, err := ParseJWT(a.Config, token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return Identity{MachineID: claims.MachineID, Scopes: []string{ScopeWildcard}}, nil
+}
+
+// looksLikeJWT reports whether token has the three dot-separated segments
+// (header.payload.signature) of a compact JWT, as opposed to a "prefix.secret"
+// scoped API token or a bare static token.
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
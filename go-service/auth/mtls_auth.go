@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MTLSAuth authenticates requests using the TLS client certificate presented
+// during the handshake (c.Request.TLS.PeerCertificates), validating it
+// against a configured CA bundle and an optional CRL, and deriving the
+// machine identity from the certificate's Subject CN or a configured SAN.
+type MTLSAuth struct {
+	// CAPool is the set of trusted root CAs a client certificate's chain
+	// must verify against.
+	CAPool *x509.CertPool
+
+	// IdentityField selects how the machine identity is derived from the
+	// peer certificate: "CN" (the default) uses the Subject Common Name,
+	// "dns" uses the first DNS SAN, "email" uses the first email SAN.
+	IdentityField string
+
+	// CRL, if non-nil, is consulted to reject certificates that have been
+	// revoked since they were issued.
+	CRL *CRLChecker
+}
+
+// NewMTLSAuth creates an MTLSAuth that verifies client certificates against
+// caPool and derives identity from identityField ("CN", "dns", or "email";
+// "" defaults to "CN"). crl may be nil to skip revocation checking.
+func NewMTLSAuth(caPool *x509.CertPool, identityField string, crl *CRLChecker) *MTLSAuth {
+	if identityField == "" {
+		identityField = "CN"
+	}
+	return &MTLSAuth{CAPool: caPool, IdentityField: identityField, CRL: crl}
+}
+
+// Authenticate implements Authenticator.
+func (a *MTLSAuth) Authenticate(c *gin.Context) (Identity, error) {
+	if c.Request.TLS == nil || len(c.Request.TLS.PeerCertificates) == 0 {
+		return Identity{}, ErrNoCredential
+	}
+
+	peerCerts := c.Request.TLS.PeerCertificates
+	cert := peerCerts[0]
+
+	intermediates := x509.NewCertPool()
+	for _, intermediate := range peerCerts[1:] {
+		intermediates.AddCert(intermediate)
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         a.CAPool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return Identity{}, fmt.Errorf("client certificate did not verify against a trusted CA: %w", err)
+	}
+
+	if a.CRL.IsRevoked(cert.SerialNumber) {
+		return Identity{}, errors.New("client certificate has been revoked")
+	}
+
+	machineID, err := a.identity(cert)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return Identity{MachineID: machineID, Scopes: []string{ScopeWildcard}}, nil
+}
+
+func (a *MTLSAuth) identity(cert *x509.Certificate) (string, error) {
+	switch a.IdentityField {
+	case "CN", "":
+		if cert.Subject.CommonName == "" {
+			return "", errors.New("client certificate has no Subject CN")
+		}
+		return cert.Subject.CommonName, nil
+	case "dns":
+		if len(cert.DNSNames) == 0 {
+			return "", errors.New("client certificate has no DNS SAN")
+		}
+		return cert.DNSNames[0], nil
+	case "email":
+		if len(cert.EmailAddresses) == 0 {
+			return "", errors.New("client certificate has no email SAN")
+		}
+		return cert.EmailAddresses[0], nil
+	default:
+		return "", fmt.Errorf("unsupported mTLS identity field %q", a.IdentityField)
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"database/sql"
+
+	"iot-sensor-service/models"
+)
+
+// PostgresWatcherStore implements WatcherStore using Postgres.
+type PostgresWatcherStore struct {
+	db *sql.DB
+}
+
+// NewPostgresWatcherStore creates a new Postgres-backed watcher store.
+func NewPostgresWatcherStore(db *sql.DB) *PostgresWatcherStore {
+	return &PostgresWatcherStore{db: db}
+}
+
+// FindByMachineID looks up a watcher by id.
+func (s *PostgresWatcherStore) FindByMachineID(machineID string) (*Watcher, error) {
+	var w Watcher
+	err := s.db.QueryRow(`
+		SELECT machine_id, hashed_password, created_at FROM watchers WHERE machine_id = $1
+	`, machineID).Scan(&w.MachineID, &w.HashedPassword, &w.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Upsert creates or replaces the credential for machineID.
+func (s *PostgresWatcherStore) Upsert(machineID, hashedPassword string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO watchers (machine_id, hashed_password, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (machine_id) DO UPDATE SET hashed_password = excluded.hashed_password
+	`, machineID, hashedPassword, models.Now())
+	return err
+}
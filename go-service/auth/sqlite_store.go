@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"iot-sensor-service/models"
+)
+
+// SQLiteTokenStore implements TokenStore using SQLite.
+type SQLiteTokenStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteTokenStore creates a new SQLite-backed token store.
+func NewSQLiteTokenStore(db *sql.DB) *SQLiteTokenStore {
+	return &SQLiteTokenStore{db: db}
+}
+
+// Count returns the total number of tokens ever created.
+func (s *SQLiteTokenStore) Count() (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM api_tokens`).Scan(&count)
+	return count, err
+}
+
+// List returns every token, most recently created first.
+func (s *SQLiteTokenStore) List() ([]Token, error) {
+	rows, err := s.db.Query(`
+		SELECT id, prefix, hashed_token, name, scopes, created_at, expires_at, revoked_at
+		FROM api_tokens ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []Token
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+
+	if tokens == nil {
+		tokens = []Token{}
+	}
+
+	return tokens, rows.Err()
+}
+
+// Create mints a new token and stores its hash.
+func (s *SQLiteTokenStore) Create(req *TokenCreate) (*Token, string, error) {
+	full, prefix, hashed, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	id := uuid.New().String()
+	createdAt := models.Now()
+	scopes := strings.Join(req.Scopes, ",")
+
+	var expiresAt *string
+	if req.ExpiresIn != nil {
+		t := expiresAtFromNow(*req.ExpiresIn)
+		expiresAt = &t
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO api_tokens (id, prefix, hashed_token, name, scopes, created_at, expires_at, revoked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULL)
+	`, id, prefix, hashed, req.Name, scopes, createdAt, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &Token{
+		ID:        id,
+		Prefix:    prefix,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
+	}, full, nil
+}
+
+// Revoke marks a token as revoked. Returns false if no token has that id.
+func (s *SQLiteTokenStore) Revoke(id string) (bool, error) {
+	result, err := s.db.Exec(`
+		UPDATE api_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL
+	`, models.Now(), id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// FindByPrefix looks up a token by its public prefix.
+func (s *SQLiteTokenStore) FindByPrefix(prefix string) (*Token, error) {
+	row := s.db.QueryRow(`
+		SELECT id, prefix, hashed_token, name, scopes, created_at, expires_at, revoked_at
+		FROM api_tokens WHERE prefix = ?
+	`, prefix)
+
+	t, err := scanToken(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// tokenScanner abstracts over *sql.Row and *sql.Rows so scanToken works for
+// both single-row lookups and result set iteration.
+type tokenScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanToken(row tokenScanner) (Token, error) {
+	var t Token
+	var scopes string
+	var expiresAt, revokedAt sql.NullString
+
+	err := row.Scan(&t.ID, &t.Prefix, &t.HashedToken, &t.Name, &scopes, &t.CreatedAt, &expiresAt, &revokedAt)
+	if err != nil {
+		return Token{}, err
+	}
+
+	if scopes != "" {
+		t.Scopes = strings.Split(scopes, ",")
+	}
+	if expiresAt.Valid {
+		t.ExpiresAt = &expiresAt.String
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.String
+	}
+
+	return t, nil
+}
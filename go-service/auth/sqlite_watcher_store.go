@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"database/sql"
+
+	"iot-sensor-service/models"
+)
+
+// SQLiteWatcherStore implements WatcherStore using SQLite.
+type SQLiteWatcherStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteWatcherStore creates a new SQLite-backed watcher store.
+func NewSQLiteWatcherStore(db *sql.DB) *SQLiteWatcherStore {
+	return &SQLiteWatcherStore{db: db}
+}
+
+// FindByMachineID looks up a watcher by id.
+func (s *SQLiteWatcherStore) FindByMachineID(machineID string) (*Watcher, error) {
+	var w Watcher
+	err := s.db.QueryRow(`
+		SELECT machine_id, hashed_password, created_at FROM watchers WHERE machine_id = ?
+	`, machineID).Scan(&w.MachineID, &w.HashedPassword, &w.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Upsert creates or replaces the credential for machineID.
+func (s *SQLiteWatcherStore) Upsert(machineID, hashedPassword string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO watchers (machine_id, hashed_password, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(machine_id) DO UPDATE SET hashed_password = excluded.hashed_password
+	`, machineID, hashedPassword, models.Now())
+	return err
+}
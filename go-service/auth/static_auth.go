@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidToken = errors.New("invalid token")
+
+// StaticTokenAuth authenticates requests against a TokenStore, falling back
+// to comparing against a single static token directly when the store has
+// never had a token created in it. This preserves the service's original
+// single-token behavior for deployments that haven't adopted the token
+// store.
+type StaticTokenAuth struct {
+	Store       TokenStore
+	StaticToken string
+}
+
+// NewStaticTokenAuth creates a StaticTokenAuth backed by store, falling back
+// to staticToken while store is empty.
+func NewStaticTokenAuth(store TokenStore, staticToken string) *StaticTokenAuth {
+	return &StaticTokenAuth{Store: store, StaticToken: staticToken}
+}
+
+// Authenticate implements Authenticator.
+func (a *StaticTokenAuth) Authenticate(c *gin.Context) (Identity, error) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return Identity{}, ErrNoCredential
+	}
+
+	count, err := a.Store.Count()
+	if err != nil {
+		return Identity{}, err
+	}
+
+	// Legacy single-token mode: no tokens have been minted yet, so fall back
+	// to comparing against the static API_TOKEN directly.
+	if count == 0 {
+		if token != a.StaticToken {
+			return Identity{}, errInvalidToken
+		}
+		return Identity{Scopes: []string{ScopeWildcard}}, nil
+	}
+
+	scopes, err := a.authenticate(token)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{Scopes: scopes}, nil
+}
+
+// authenticate looks up a token by its prefix, verifies its hash, and checks
+// expiry/revocation, returning its scopes if valid.
+func (a *StaticTokenAuth) authenticate(token string) ([]string, error) {
+	prefix, ok := SplitToken(token)
+	if !ok {
+		return nil, errInvalidToken
+	}
+
+	record, err := a.Store.FindByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errInvalidToken
+	}
+	if !Verify(record.HashedToken, token) {
+		return nil, errInvalidToken
+	}
+	if record.RevokedAt != nil {
+		return nil, errInvalidToken
+	}
+	if record.ExpiresAt != nil {
+		expiresAt, err := time.Parse(time.RFC3339, *record.ExpiresAt)
+		if err == nil && time.Now().UTC().After(expiresAt) {
+			return nil, errInvalidToken
+		}
+	}
+
+	return record.Scopes, nil
+}
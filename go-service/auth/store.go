@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TokenStore defines the interface for persisting and verifying API tokens.
+type TokenStore interface {
+	// Count returns the number of tokens ever created, including revoked
+	// and expired ones. Used to decide whether legacy single-token mode is
+	// still in effect (an empty table).
+	Count() (int, error)
+
+	// List returns every token, most recently created first.
+	List() ([]Token, error)
+
+	// Create mints a new token with the given name and scopes, returning the
+	// stored record and the plaintext secret the caller must save now.
+	Create(req *TokenCreate) (*Token, string, error)
+
+	// Revoke marks a token as revoked. Returns sql.ErrNoRows analogues
+	// (nil, nil) semantics are not used here; repositories return
+	// (false, nil) when no row matched, consistent with a no-op delete.
+	Revoke(id string) (bool, error)
+
+	// FindByPrefix looks up a non-revoked token by its public prefix for use
+	// during authentication. Returns nil if no token has that prefix.
+	FindByPrefix(prefix string) (*Token, error)
+}
+
+// tokenPrefixLen is the length, in hex characters, of the public prefix used
+// to index tokens for lookup without exposing the secret.
+const tokenPrefixLen = 8
+
+// secretLen is the length, in random bytes, of the secret portion of a token.
+const secretLen = 24
+
+// generateToken creates a new random token of the form "<prefix>.<secret>"
+// along with its bcrypt hash for storage.
+func generateToken() (full, prefix, hashed string, err error) {
+	prefixBytes := make([]byte, tokenPrefixLen/2)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	secretBytes := make([]byte, secretLen)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	full = fmt.Sprintf("%s.%s", prefix, hex.EncodeToString(secretBytes))
+
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(full), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return full, prefix, string(hashedBytes), nil
+}
+
+// SplitToken extracts the public prefix from a presented bearer token, e.g.
+// for use as a lookup key. Returns ok=false if the token is malformed.
+func SplitToken(token string) (prefix string, ok bool) {
+	idx := strings.IndexByte(token, '.')
+	if idx <= 0 {
+		return "", false
+	}
+	return token[:idx], true
+}
+
+// Verify checks a presented plaintext token against a stored hash.
+func Verify(hashedToken, presentedToken string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashedToken), []byte(presentedToken)) == nil
+}
+
+// HashSecret bcrypt-hashes an arbitrary secret (e.g. a watcher password) for
+// storage. Unlike generateToken, it hashes a caller-supplied value rather
+// than minting a new random one.
+func HashSecret(secret string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// expiresAtFromNow returns an ISO 8601 timestamp expiresInSeconds in the
+// future, matching the timestamp format used throughout the rest of the
+// service.
+func expiresAtFromNow(expiresInSeconds int) string {
+	return time.Now().UTC().Add(time.Duration(expiresInSeconds) * time.Second).Format(time.RFC3339)
+}
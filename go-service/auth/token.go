@@ -0,0 +1,70 @@
+package auth
+
+// Scope names recognized by the service. A token's scopes gate which
+// endpoints it may call; see middleware.RequireScope.
+const (
+	ScopeSensorsRead    = "sensors:read"
+	ScopeSensorsWrite   = "sensors:write"
+	ScopeReadingsIngest = "readings:ingest"
+	ScopeTriggersRead   = "triggers:read"
+	ScopeTriggersWrite  = "triggers:write"
+)
+
+// ValidScopes contains all scopes that can be granted to a token.
+var ValidScopes = map[string]bool{
+	ScopeSensorsRead:    true,
+	ScopeSensorsWrite:   true,
+	ScopeReadingsIngest: true,
+	ScopeTriggersRead:   true,
+	ScopeTriggersWrite:  true,
+}
+
+// ScopeWildcard is the synthetic scope granted to requests authenticated via
+// the legacy single-token mode, so they keep working against every route.
+const ScopeWildcard = "*"
+
+// Token represents a row in the api_tokens table. HashedToken is never
+// serialized back to clients.
+type Token struct {
+	ID          string     `json:"id"`
+	Prefix      string     `json:"-"`
+	HashedToken string     `json:"-"`
+	Name        string     `json:"name"`
+	Scopes      []string   `json:"scopes"`
+	CreatedAt   string     `json:"created_at"`
+	ExpiresAt   *string    `json:"expires_at,omitempty"`
+	RevokedAt   *string    `json:"revoked_at,omitempty"`
+}
+
+// TokenCreate represents the request body for minting a new token.
+type TokenCreate struct {
+	Name      string   `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []string `json:"scopes" binding:"required,min=1"`
+	ExpiresIn *int     `json:"expires_in_seconds,omitempty"`
+}
+
+// Validate checks that every requested scope is recognized.
+func (t *TokenCreate) Validate() error {
+	for _, scope := range t.Scopes {
+		if !ValidScopes[scope] {
+			return &InvalidScopeError{Scope: scope}
+		}
+	}
+	return nil
+}
+
+// InvalidScopeError indicates a token creation request named an unknown scope.
+type InvalidScopeError struct {
+	Scope string
+}
+
+func (e *InvalidScopeError) Error() string {
+	return "invalid scope: " + e.Scope
+}
+
+// TokenCreateResponse is returned once, at creation time, and is the only
+// place the plaintext token is ever exposed.
+type TokenCreateResponse struct {
+	Token  Token  `json:"token"`
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,26 @@
+package auth
+
+// Watcher represents a machine_id/password credential used to obtain JWTs
+// from the /v1/watchers/login endpoint, mirroring the crowdsec LAPI "watcher"
+// concept of a registered agent rather than a scoped API client.
+type Watcher struct {
+	MachineID      string `json:"machine_id"`
+	HashedPassword string `json:"-"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// WatcherLogin represents the request body for POST /v1/watchers/login.
+type WatcherLogin struct {
+	MachineID string `json:"machine_id" binding:"required"`
+	Password  string `json:"password" binding:"required"`
+}
+
+// WatcherStore defines the interface for persisting watcher credentials.
+type WatcherStore interface {
+	// FindByMachineID looks up a watcher by id. Returns nil if none exists.
+	FindByMachineID(machineID string) (*Watcher, error)
+
+	// Upsert creates or replaces the credential for machineID, used to
+	// bootstrap a watcher from WATCHER_MACHINE_ID/WATCHER_PASSWORD at startup.
+	Upsert(machineID, hashedPassword string) error
+}
@@ -4,16 +4,34 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds application configuration loaded from environment variables.
 type Config struct {
-	Port         int
-	DatabasePath string
-	APIToken     string
-	LogLevel     string
-	LogFormat    string
-	SeedDataPath string
+	Port                   int
+	DatabaseURL            string
+	DatabasePath           string
+	APIToken               string
+	RootToken              string
+	LogLevel               string
+	LogFormat              string
+	SeedDataPath           string
+	MetricsToken           string
+	MetricsRefreshInterval time.Duration
+	AuthMode               string
+	JWTSecret              string
+	JWTExpiry              time.Duration
+	JWTRefreshWindow       time.Duration
+	WatcherMachineID       string
+	WatcherPassword        string
+	TLS                    *TLSConfig
+	TriggerWorkers         int
+	TriggerMaxAttempts     int
+	RetentionCheckInterval time.Duration
+	RetentionRawDays       int
+	RetentionRawDaysByType map[string]int
 }
 
 // Load reads configuration from environment variables.
@@ -28,13 +46,105 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("API_TOKEN environment variable is required")
 	}
 
+	databasePath := getEnv("DATABASE_PATH", "/app/data/sensors-go.db")
+
+	// DATABASE_URL selects the backend driver (sqlite:// or postgres://).
+	// Falls back to DATABASE_PATH as a local SQLite file for operators who
+	// haven't migrated to the DSN-based config yet.
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		databaseURL = "sqlite://" + databasePath
+	}
+
+	metricsRefreshInterval, err := time.ParseDuration(getEnv("METRICS_REFRESH_INTERVAL", "30s"))
+	if err != nil {
+		metricsRefreshInterval = 30 * time.Second
+	}
+
+	// ROOT_TOKEN gates the token-management admin endpoints. It defaults to
+	// API_TOKEN so existing deployments can start minting scoped tokens
+	// without provisioning a separate secret first.
+	rootToken := getEnv("ROOT_TOKEN", apiToken)
+
+	// AUTH_MODE selects which of AuthMiddleware's credential checks run:
+	// "static" (the original API_TOKEN/token-store check, the default),
+	// "jwt" (machine_id/password login issuing short-lived JWTs), or "both".
+	authMode := getEnv("AUTH_MODE", "static")
+	if authMode != "static" && authMode != "jwt" && authMode != "both" {
+		return nil, fmt.Errorf("invalid AUTH_MODE %q: must be static, jwt, or both", authMode)
+	}
+
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if (authMode == "jwt" || authMode == "both") && jwtSecret == "" {
+		return nil, fmt.Errorf("JWT_SECRET environment variable is required when AUTH_MODE is %q", authMode)
+	}
+
+	jwtExpiry, err := time.ParseDuration(getEnv("JWT_EXPIRY", "15m"))
+	if err != nil {
+		jwtExpiry = 15 * time.Minute
+	}
+
+	jwtRefreshWindow, err := time.ParseDuration(getEnv("JWT_REFRESH_WINDOW", "5m"))
+	if err != nil {
+		jwtRefreshWindow = 5 * time.Minute
+	}
+
+	tlsCfg, err := LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	triggerWorkers, err := strconv.Atoi(getEnv("TRIGGER_WORKERS", "4"))
+	if err != nil || triggerWorkers <= 0 {
+		triggerWorkers = 4
+	}
+
+	triggerMaxAttempts, err := strconv.Atoi(getEnv("TRIGGER_MAX_ATTEMPTS", "5"))
+	if err != nil || triggerMaxAttempts <= 0 {
+		triggerMaxAttempts = 5
+	}
+
+	retentionCheckInterval, err := time.ParseDuration(getEnv("RETENTION_CHECK_INTERVAL", "1h"))
+	if err != nil {
+		retentionCheckInterval = time.Hour
+	}
+
+	retentionRawDays, err := strconv.Atoi(getEnv("RETENTION_RAW_DAYS", "7"))
+	if err != nil || retentionRawDays <= 0 {
+		retentionRawDays = 7
+	}
+
+	// RETENTION_RAW_DAYS_BY_TYPE overrides RETENTION_RAW_DAYS per sensor
+	// type, e.g. "motion=3,co2=14", for sensor types whose readings should
+	// be kept for a different window than the default.
+	retentionRawDaysByType, err := parseRetentionDaysByType(os.Getenv("RETENTION_RAW_DAYS_BY_TYPE"))
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		Port:         port,
-		DatabasePath: getEnv("DATABASE_PATH", "/app/data/sensors-go.db"),
-		APIToken:     apiToken,
-		LogLevel:     getEnv("LOG_LEVEL", "INFO"),
-		LogFormat:    getEnv("LOG_FORMAT", "json"),
-		SeedDataPath: getEnv("SEED_DATA_PATH", "/app/data/sensors.json"),
+		Port:                   port,
+		DatabaseURL:            databaseURL,
+		DatabasePath:           databasePath,
+		APIToken:               apiToken,
+		RootToken:              rootToken,
+		LogLevel:               getEnv("LOG_LEVEL", "INFO"),
+		LogFormat:              getEnv("LOG_FORMAT", "json"),
+		SeedDataPath:           getEnv("SEED_DATA_PATH", "/app/data/sensors.json"),
+		MetricsToken:           os.Getenv("METRICS_TOKEN"),
+		MetricsRefreshInterval: metricsRefreshInterval,
+		AuthMode:               authMode,
+		JWTSecret:              jwtSecret,
+		JWTExpiry:              jwtExpiry,
+		JWTRefreshWindow:       jwtRefreshWindow,
+		WatcherMachineID:       os.Getenv("WATCHER_MACHINE_ID"),
+		WatcherPassword:        os.Getenv("WATCHER_PASSWORD"),
+		TLS:                    tlsCfg,
+		TriggerWorkers:         triggerWorkers,
+		TriggerMaxAttempts:     triggerMaxAttempts,
+		RetentionCheckInterval: retentionCheckInterval,
+		RetentionRawDays:       retentionRawDays,
+		RetentionRawDaysByType: retentionRawDaysByType,
 	}, nil
 }
 
@@ -45,3 +155,29 @@ func getEnv(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+// parseRetentionDaysByType parses a "type=days,type=days" list into a map,
+// returning an empty map for an empty string.
+func parseRetentionDaysByType(s string) (map[string]int, error) {
+	result := make(map[string]int)
+	if s == "" {
+		return result, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid RETENTION_RAW_DAYS_BY_TYPE entry %q: expected \"type=days\"", pair)
+		}
+
+		sensorType := strings.TrimSpace(parts[0])
+		days, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid RETENTION_RAW_DAYS_BY_TYPE entry %q: days must be a positive integer", pair)
+		}
+
+		result[sensorType] = days
+	}
+
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TLSConfig holds the certificate material and client-auth policy for
+// serving HTTPS, optionally with mutual TLS. Populated from TLS_*
+// environment variables; every field is optional, in which case the
+// service falls back to plain HTTP (see Enabled).
+type TLSConfig struct {
+	CACertPath      string
+	ServerCertPath  string
+	ServerKeyPath   string
+	ClientAuthType  string
+	IdentityField   string
+	CRLPath         string
+	CRLPollInterval time.Duration
+}
+
+// LoadTLSConfig reads TLS configuration from TLS_* environment variables.
+func LoadTLSConfig() (*TLSConfig, error) {
+	clientAuthType := getEnv("TLS_CLIENT_AUTH_TYPE", "none")
+	switch clientAuthType {
+	case "none", "request", "require", "verify", "require-and-verify":
+	default:
+		return nil, fmt.Errorf("invalid TLS_CLIENT_AUTH_TYPE %q: must be none, request, require, verify, or require-and-verify", clientAuthType)
+	}
+
+	crlPollInterval, err := time.ParseDuration(getEnv("TLS_CRL_POLL_INTERVAL", "5m"))
+	if err != nil {
+		crlPollInterval = 5 * time.Minute
+	}
+
+	return &TLSConfig{
+		CACertPath:      os.Getenv("TLS_CA_CERT_PATH"),
+		ServerCertPath:  os.Getenv("TLS_SERVER_CERT_PATH"),
+		ServerKeyPath:   os.Getenv("TLS_SERVER_KEY_PATH"),
+		ClientAuthType:  clientAuthType,
+		IdentityField:   getEnv("TLS_IDENTITY_FIELD", "CN"),
+		CRLPath:         os.Getenv("TLS_CRL_PATH"),
+		CRLPollInterval: crlPollInterval,
+	}, nil
+}
+
+// Enabled reports whether enough certificate material is configured for the
+// server to listen on HTTPS.
+func (c *TLSConfig) Enabled() bool {
+	return c.ServerCertPath != "" && c.ServerKeyPath != ""
+}
+
+// MTLSEnabled reports whether ClientAuthType requires or verifies client
+// certificates, i.e. whether auth.MTLSAuth should be wired in.
+func (c *TLSConfig) MTLSEnabled() bool {
+	switch c.ClientAuthType {
+	case "require", "verify", "require-and-verify":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetTLSConfig builds a *tls.Config suitable for http.Server, loading the
+// server certificate/key and, if CACertPath is set, the CA bundle used to
+// verify client certificates during the handshake.
+func (c *TLSConfig) GetTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.ServerCertPath, c.ServerKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate/key: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   clientAuthTypeFromString(c.ClientAuthType),
+	}
+
+	if c.CACertPath != "" {
+		caPool, err := LoadCAPool(c.CACertPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.ClientCAs = caPool
+	}
+
+	return tlsCfg, nil
+}
+
+// LoadCAPool reads a PEM-encoded CA bundle from path into a *x509.CertPool,
+// for use as either TLSConfig.ClientCAs or auth.MTLSAuth.CAPool.
+func LoadCAPool(path string) (*x509.CertPool, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle %q: %w", path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %q", path)
+	}
+	return pool, nil
+}
+
+func clientAuthTypeFromString(s string) tls.ClientAuthType {
+	switch s {
+	case "request":
+		return tls.RequestClientCert
+	case "require":
+		return tls.RequireAnyClientCert
+	case "verify":
+		return tls.VerifyClientCertIfGiven
+	case "require-and-verify":
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
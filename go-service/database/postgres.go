@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+// Schema for sensors and readings tables, using Postgres-native types.
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS sensors (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK(type IN ('temperature', 'motion', 'humidity', 'light', 'air_quality', 'co2', 'contact', 'pressure')),
+    location TEXT NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    unit TEXT NOT NULL,
+    status TEXT NOT NULL CHECK(status IN ('active', 'inactive', 'error')),
+    last_reading TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+    updated_at TEXT NOT NULL DEFAULT to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+);
+
+CREATE INDEX IF NOT EXISTS idx_sensors_type ON sensors(type);
+CREATE INDEX IF NOT EXISTS idx_sensors_location ON sensors(location);
+CREATE INDEX IF NOT EXISTS idx_sensors_status ON sensors(status);
+
+CREATE TABLE IF NOT EXISTS readings (
+    id BIGSERIAL PRIMARY KEY,
+    sensor_id TEXT NOT NULL REFERENCES sensors(id) ON DELETE CASCADE,
+    value DOUBLE PRECISION NOT NULL,
+    unit TEXT NOT NULL,
+    taken_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_readings_sensor_id ON readings(sensor_id);
+CREATE INDEX IF NOT EXISTS idx_readings_taken_at ON readings(taken_at);
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id TEXT PRIMARY KEY,
+    prefix TEXT NOT NULL UNIQUE,
+    hashed_token TEXT NOT NULL,
+    name TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+    expires_at TEXT,
+    revoked_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_prefix ON api_tokens(prefix);
+
+CREATE TABLE IF NOT EXISTS watchers (
+    machine_id TEXT PRIMARY KEY,
+    hashed_password TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+);
+`
+
+// PostgresDriver implements Driver for Postgres, connecting via lib/pq.
+type PostgresDriver struct {
+	DSN string
+}
+
+// Name returns the driver's short identifier.
+func (d *PostgresDriver) Name() string { return "postgres" }
+
+// Connect opens a connection pool to the Postgres server described by d.DSN.
+func (d *PostgresDriver) Connect(ctx context.Context) (*sql.DB, error) {
+	db, err := sql.Open("postgres", d.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// InitSchema creates the sensors/readings schema if it does not already exist.
+func (d *PostgresDriver) InitSchema(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, postgresSchema)
+	return err
+}
+
+// Placeholder returns the Postgres positional bind-variable placeholder
+// ($1, $2, ...) for the nth (1-indexed) argument.
+func (d *PostgresDriver) Placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"log/slog"
@@ -11,8 +12,8 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// Schema for sensors table
-const schema = `
+// Schema for sensors and readings tables
+const sqliteSchema = `
 CREATE TABLE IF NOT EXISTS sensors (
     id TEXT PRIMARY KEY,
     name TEXT NOT NULL,
@@ -29,6 +30,61 @@ CREATE TABLE IF NOT EXISTS sensors (
 CREATE INDEX IF NOT EXISTS idx_sensors_type ON sensors(type);
 CREATE INDEX IF NOT EXISTS idx_sensors_location ON sensors(location);
 CREATE INDEX IF NOT EXISTS idx_sensors_status ON sensors(status);
+
+CREATE TABLE IF NOT EXISTS readings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sensor_id TEXT NOT NULL REFERENCES sensors(id) ON DELETE CASCADE,
+    value REAL NOT NULL,
+    unit TEXT NOT NULL,
+    taken_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_readings_sensor_id ON readings(sensor_id);
+CREATE INDEX IF NOT EXISTS idx_readings_taken_at ON readings(taken_at);
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id TEXT PRIMARY KEY,
+    prefix TEXT NOT NULL UNIQUE,
+    hashed_token TEXT NOT NULL,
+    name TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TEXT,
+    revoked_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_prefix ON api_tokens(prefix);
+
+CREATE TABLE IF NOT EXISTS watchers (
+    machine_id TEXT PRIMARY KEY,
+    hashed_password TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS triggers (
+    id TEXT PRIMARY KEY,
+    sensor_id TEXT NOT NULL REFERENCES sensors(id) ON DELETE CASCADE,
+    condition TEXT NOT NULL,
+    webhook_url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_triggers_sensor_id ON triggers(sensor_id);
+
+CREATE TABLE IF NOT EXISTS trigger_dead_letters (
+    id TEXT PRIMARY KEY,
+    trigger_id TEXT NOT NULL REFERENCES triggers(id) ON DELETE CASCADE,
+    correlation_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trigger_dead_letters_trigger_id ON trigger_dead_letters(trigger_id);
 `
 
 // SensorJSON represents a sensor from the JSON seed file.
@@ -51,7 +107,7 @@ func Connect(dbPath string) (*sql.DB, error) {
 		return nil, err
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
 	if err != nil {
 		return nil, err
 	}
@@ -66,10 +122,42 @@ func Connect(dbPath string) (*sql.DB, error) {
 
 // InitSchema creates the database schema.
 func InitSchema(db *sql.DB) error {
-	_, err := db.Exec(schema)
+	_, err := db.Exec(sqliteSchema)
 	return err
 }
 
+// SQLiteDriver implements Driver for a local SQLite file, parsed from a
+// "sqlite:///path/to.db" DSN.
+type SQLiteDriver struct {
+	Path string
+}
+
+// Name returns the driver's short identifier.
+func (d *SQLiteDriver) Name() string { return "sqlite" }
+
+// Connect opens the SQLite file described by d.Path, creating its parent
+// directory if necessary.
+func (d *SQLiteDriver) Connect(ctx context.Context) (*sql.DB, error) {
+	db, err := Connect(d.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// InitSchema creates the sensors/readings schema if it does not already exist.
+func (d *SQLiteDriver) InitSchema(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, sqliteSchema)
+	return err
+}
+
+// Placeholder returns the SQLite bind-variable placeholder, which does not
+// depend on the argument's position.
+func (d *SQLiteDriver) Placeholder(n int) string { return "?" }
+
 // SeedFromJSON seeds the database from a JSON file if the table is empty.
 func SeedFromJSON(db *sql.DB, jsonPath string) error {
 	// Check if table has data
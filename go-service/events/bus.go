@@ -0,0 +1,158 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event type names published by sensor/reading handlers and the trigger
+// evaluator.
+const (
+	SensorCreated   = "sensor.created"
+	SensorUpdated   = "sensor.updated"
+	SensorDeleted   = "sensor.deleted"
+	ReadingIngested = "reading.ingested"
+	TriggerFired    = "trigger.fired"
+)
+
+// ringBufferSize bounds how many past events a reconnecting client can
+// replay via Last-Event-ID.
+const ringBufferSize = 1024
+
+// subscriberBufferSize bounds how many events a single subscriber can lag
+// behind the publisher before its SlowConsumerPolicy kicks in.
+const subscriberBufferSize = 64
+
+// SlowConsumerPolicy controls what Publish does for a subscriber whose
+// buffered channel is full.
+type SlowConsumerPolicy int
+
+const (
+	// DropOldest evicts the subscriber's oldest buffered event to make room
+	// for the new one, so the stream keeps moving but the client skips ahead
+	// to more recent events. This is the default for SSE clients, who can
+	// notice the gap via the skipped Seq numbers and resume from Last-Event-ID.
+	DropOldest SlowConsumerPolicy = iota
+	// Disconnect closes the subscriber's channel outright, ending its SSE
+	// stream so the client reconnects (with a Retry-After hint) instead of
+	// silently falling further and further behind.
+	Disconnect
+)
+
+// Event is a single entry published to the bus. Seq is monotonically
+// increasing so a client can resume a stream after reconnecting.
+type Event struct {
+	Seq           uint64      `json:"seq"`
+	Type          string      `json:"type"`
+	Timestamp     string      `json:"timestamp"`
+	CorrelationID string      `json:"correlation_id,omitempty"`
+	Data          interface{} `json:"data"`
+}
+
+// subscriber is one registered listener's buffered channel and the policy
+// Publish applies to it when that buffer fills up.
+type subscriber struct {
+	ch     chan Event
+	policy SlowConsumerPolicy
+}
+
+// Bus is an in-process publish/subscribe event bus with a bounded replay
+// buffer. It lets SSE handlers subscribe to live events while also
+// replaying a client's missed backlog from a given sequence number.
+type Bus struct {
+	mu          sync.Mutex
+	nextSeq     uint64
+	ring        []Event
+	subscribers map[*subscriber]struct{}
+}
+
+// NewBus creates an empty event bus.
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish appends a new event to the ring buffer and fans it out to every
+// current subscriber, applying each subscriber's SlowConsumerPolicy if it
+// can't keep up.
+func (b *Bus) Publish(eventType string, data interface{}, correlationID string) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextSeq++
+	event := Event{
+		Seq:           b.nextSeq,
+		Type:          eventType,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		CorrelationID: correlationID,
+		Data:          data,
+	}
+
+	b.ring = append(b.ring, event)
+	if len(b.ring) > ringBufferSize {
+		b.ring = b.ring[len(b.ring)-ringBufferSize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+			continue
+		default:
+		}
+
+		switch sub.policy {
+		case Disconnect:
+			delete(b.subscribers, sub)
+			close(sub.ch)
+		default: // DropOldest
+			select {
+			case <-sub.ch:
+			default:
+			}
+			select {
+			case sub.ch <- event:
+			default:
+			}
+		}
+	}
+
+	return event
+}
+
+// Subscribe registers a new subscriber, applying policy when its buffer
+// fills up, and returns its channel along with an unsubscribe function the
+// caller must invoke when it stops listening.
+func (b *Bus) Subscribe(policy SlowConsumerPolicy) (chan Event, func()) {
+	sub := &subscriber{ch: make(chan Event, subscriberBufferSize), policy: policy}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[sub]; ok {
+			delete(b.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+
+	return sub.ch, unsubscribe
+}
+
+// Since returns buffered events with a sequence number greater than after,
+// in publish order. Events older than the ring buffer's retention are lost.
+func (b *Bus) Since(after uint64) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var result []Event
+	for _, e := range b.ring {
+		if e.Seq > after {
+			result = append(result, e)
+		}
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/auth"
+	"iot-sensor-service/models"
+)
+
+// AdminTokenHandler handles API token issuance and management, gated by the
+// bootstrap root token rather than the token store it manages.
+type AdminTokenHandler struct {
+	store auth.TokenStore
+}
+
+// NewAdminTokenHandler creates a new admin token handler for the given store.
+func NewAdminTokenHandler(store auth.TokenStore) *AdminTokenHandler {
+	return &AdminTokenHandler{store: store}
+}
+
+// CreateToken mints a new API token and returns its plaintext secret. The
+// secret is never recoverable again after this response.
+func (h *AdminTokenHandler) CreateToken(c *gin.Context) {
+	var req auth.TokenCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Invalid request body",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	token, secret, err := h.store.Create(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, auth.TokenCreateResponse{
+		Token:  *token,
+		Secret: secret,
+	})
+}
+
+// ListTokens returns all tokens, without their hashes or secrets.
+func (h *AdminTokenHandler) ListTokens(c *gin.Context) {
+	tokens, err := h.store.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to retrieve tokens",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"count":  len(tokens),
+	})
+}
+
+// RevokeToken revokes a token by id, preventing it from authenticating
+// further requests while preserving its record for audit purposes.
+func (h *AdminTokenHandler) RevokeToken(c *gin.Context) {
+	id := c.Param("id")
+
+	revoked, err := h.store.Revoke(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to revoke token",
+		})
+		return
+	}
+
+	if !revoked {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:  "Token not found",
+			Detail: "No active token with id '" + id + "'",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": true})
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/events"
+	"iot-sensor-service/models"
+	"iot-sensor-service/triggers"
+)
+
+// retryHintMillis is sent to SSE clients as a `retry:` field so they know
+// how long to wait before reconnecting, including after a slow-consumer
+// disconnect (see events.Disconnect).
+const retryHintMillis = 3000
+
+// sensorEventTypes are the event types streamed by StreamSensorEvents and
+// StreamSensorEventsByID: sensor lifecycle changes and the trigger firings
+// they lead to.
+var sensorEventTypes = map[string]bool{
+	events.SensorCreated: true,
+	events.SensorUpdated: true,
+	events.SensorDeleted: true,
+	events.TriggerFired:  true,
+}
+
+// EventHandler streams sensor/reading lifecycle events over Server-Sent Events.
+type EventHandler struct {
+	bus *events.Bus
+}
+
+// NewEventHandler creates a new event handler backed by the given bus.
+func NewEventHandler(bus *events.Bus) *EventHandler {
+	return &EventHandler{bus: bus}
+}
+
+// Stream upgrades the request to an SSE stream, optionally filtered by
+// `types` (comma-separated), replaying missed events first when the client
+// supplies `Last-Event-ID` (or a `since` query param as a fallback).
+func (h *EventHandler) Stream(c *gin.Context) {
+	typeFilter := parseTypeFilter(c.Query("types"))
+	h.stream(c, typeFilter, "")
+}
+
+// StreamSensorEvents upgrades the request to an SSE stream of every sensor's
+// lifecycle events (created, updated, deleted) and trigger firings.
+func (h *EventHandler) StreamSensorEvents(c *gin.Context) {
+	h.stream(c, sensorEventTypes, "")
+}
+
+// StreamSensorEventsByID is like StreamSensorEvents, scoped to a single
+// sensor's id.
+func (h *EventHandler) StreamSensorEventsByID(c *gin.Context) {
+	h.stream(c, sensorEventTypes, c.Param("id"))
+}
+
+// stream runs the common SSE loop: replay missed events since Last-Event-ID,
+// then fan out live ones, filtering by eventType (nil matches everything)
+// and, if sensorID is non-empty, by the sensor the event is about.
+func (h *EventHandler) stream(c *gin.Context, typeFilter map[string]bool, sensorID string) {
+	since := parseSince(c)
+
+	ch, unsubscribe := h.bus.Subscribe(parseSlowConsumerPolicy(c))
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", retryHintMillis)
+
+	for _, event := range h.bus.Since(since) {
+		if eventMatches(event, typeFilter, sensorID) {
+			writeSSEEvent(c.Writer, event)
+		}
+	}
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if eventMatches(event, typeFilter, sensorID) {
+				writeSSEEvent(c.Writer, event)
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+func parseTypeFilter(typesParam string) map[string]bool {
+	if typesParam == "" {
+		return nil
+	}
+
+	filter := make(map[string]bool)
+	for _, t := range strings.Split(typesParam, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			filter[t] = true
+		}
+	}
+	return filter
+}
+
+// parseSlowConsumerPolicy reads `on_backpressure` (`drop` or `disconnect`),
+// defaulting to drop-oldest so an overwhelmed client skips ahead rather than
+// losing its connection outright.
+func parseSlowConsumerPolicy(c *gin.Context) events.SlowConsumerPolicy {
+	if c.Query("on_backpressure") == "disconnect" {
+		return events.Disconnect
+	}
+	return events.DropOldest
+}
+
+func parseSince(c *gin.Context) uint64 {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("since")
+	}
+	since, _ := strconv.ParseUint(raw, 10, 64)
+	return since
+}
+
+func eventMatches(event events.Event, typeFilter map[string]bool, sensorID string) bool {
+	if typeFilter != nil && !typeFilter[event.Type] {
+		return false
+	}
+	if sensorID == "" {
+		return true
+	}
+	return eventSensorID(event) == sensorID
+}
+
+// eventSensorID extracts the sensor id an event is about, if any, so
+// per-sensor streams can filter to it. Event payloads vary by type (see
+// handlers/sensors.go, handlers/readings.go, triggers.Evaluator), so this is
+// a type switch over the shapes actually published rather than a shared
+// interface.
+func eventSensorID(event events.Event) string {
+	switch data := event.Data.(type) {
+	case *models.Sensor:
+		return data.ID
+	case models.Sensor:
+		return data.ID
+	case *models.Reading:
+		return data.SensorID
+	case models.Reading:
+		return data.SensorID
+	case gin.H:
+		id, _ := data["id"].(string)
+		return id
+	case triggers.FiredEvent:
+		return data.SensorID
+	default:
+		return ""
+	}
+}
+
+func writeSSEEvent(w io.Writer, event events.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsHandler serves the Prometheus scrape endpoint, optionally gated by
+// a separate metrics token instead of the regular API token.
+type MetricsHandler struct {
+	token   string
+	wrapped http.Handler
+}
+
+// NewMetricsHandler creates a metrics handler. If token is non-empty,
+// requests must supply it via "Authorization: Bearer <token>".
+func NewMetricsHandler(token string) *MetricsHandler {
+	return &MetricsHandler{token: token, wrapped: promhttp.Handler()}
+}
+
+// Metrics serves the current Prometheus metrics in text exposition format.
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	if h.token != "" {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "Bearer "+h.token {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or missing metrics token",
+			})
+			return
+		}
+	}
+
+	h.wrapped.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/events"
+	"iot-sensor-service/middleware"
+	"iot-sensor-service/models"
+	"iot-sensor-service/repositories"
+	"iot-sensor-service/triggers"
+)
+
+// ReadingHandler handles sensor reading ingest and query operations.
+type ReadingHandler struct {
+	repo       repositories.ReadingRepository
+	sensorRepo repositories.SensorRepository
+	bus        *events.Bus
+	evaluator  *triggers.Evaluator
+}
+
+// NewReadingHandler creates a new reading handler with the given repository,
+// publishing a ReadingIngested event to bus and checking evaluator's
+// triggers against the updated sensor for each reading that's stored.
+func NewReadingHandler(repo repositories.ReadingRepository, sensorRepo repositories.SensorRepository, bus *events.Bus, evaluator *triggers.Evaluator) *ReadingHandler {
+	return &ReadingHandler{repo: repo, sensorRepo: sensorRepo, bus: bus, evaluator: evaluator}
+}
+
+// evaluateTriggers fetches sensorID's current state and checks it against
+// its registered triggers, ignoring lookup failures since this runs after
+// the response-relevant work has already succeeded.
+func (h *ReadingHandler) evaluateTriggers(sensorID, correlationID string) {
+	sensor, err := h.sensorRepo.GetByID(sensorID)
+	if err != nil || sensor == nil {
+		return
+	}
+	h.evaluator.EvaluateSensor(sensor, correlationID)
+}
+
+// IngestReading accepts a single reading or a batch (`{"readings": [...]}`)
+// for a sensor and updates the sensor's current value in the same transaction.
+func (h *ReadingHandler) IngestReading(c *gin.Context) {
+	sensorID := c.Param("id")
+
+	correlationID := middleware.GetCorrelationID(c)
+
+	var batch models.ReadingBatchCreate
+	if err := c.ShouldBindBodyWithJSON(&batch); err == nil && len(batch.Readings) > 0 {
+		readings, err := h.repo.CreateMany(sensorID, batch.Readings)
+		if err != nil {
+			h.respondCreateError(c, sensorID, err)
+			return
+		}
+		for _, reading := range readings {
+			h.bus.Publish(events.ReadingIngested, reading, correlationID)
+		}
+		h.evaluateTriggers(sensorID, correlationID)
+		c.JSON(http.StatusCreated, models.ReadingList{
+			Readings: readings,
+			Count:    len(readings),
+		})
+		return
+	}
+
+	var input models.ReadingCreate
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Invalid request body",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	reading, err := h.repo.Create(sensorID, &input)
+	if err != nil {
+		h.respondCreateError(c, sensorID, err)
+		return
+	}
+
+	h.bus.Publish(events.ReadingIngested, reading, correlationID)
+	h.evaluateTriggers(sensorID, correlationID)
+
+	c.JSON(http.StatusCreated, reading)
+}
+
+// IngestBatch accepts readings for multiple sensors in one request
+// (`{"readings": [{"sensor_id": ..., "value": ..., "unit": ...}, ...]}`),
+// ingesting them in a single transaction.
+func (h *ReadingHandler) IngestBatch(c *gin.Context) {
+	var batch models.ReadingCrossBatchCreate
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Invalid request body",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	readings, err := h.repo.CreateBatch(batch.Readings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Failed to ingest readings",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	correlationID := middleware.GetCorrelationID(c)
+	evaluated := make(map[string]bool, len(readings))
+	for _, reading := range readings {
+		h.bus.Publish(events.ReadingIngested, reading, correlationID)
+		if !evaluated[reading.SensorID] {
+			h.evaluateTriggers(reading.SensorID, correlationID)
+			evaluated[reading.SensorID] = true
+		}
+	}
+
+	c.JSON(http.StatusCreated, models.ReadingList{
+		Readings: readings,
+		Count:    len(readings),
+	})
+}
+
+func (h *ReadingHandler) respondCreateError(c *gin.Context, sensorID string, err error) {
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:  "Sensor not found",
+			Detail: "No sensor with id '" + sensorID + "'",
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:  "Failed to ingest reading",
+		Detail: err.Error(),
+	})
+}
+
+// ListReadings returns readings for a sensor within an optional time range.
+// If step or agg is present, the response is downsampled into buckets (via
+// the same aggregation the /aggregate endpoint uses) instead of returning
+// raw readings; "mean" is accepted as an alias for "avg" to match this
+// endpoint's documented agg values.
+func (h *ReadingHandler) ListReadings(c *gin.Context) {
+	sensorID := c.Param("id")
+
+	from := c.Query("from")
+	to := c.Query("to")
+	order := c.DefaultQuery("order", "asc")
+
+	if step := c.Query("step"); step != "" || c.Query("agg") != "" {
+		if step == "" {
+			step = "1h"
+		}
+		agg := c.DefaultQuery("agg", "mean")
+		if agg == "mean" {
+			agg = "avg"
+		}
+
+		buckets, err := h.repo.Aggregate(sensorID, from, to, step, agg)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:  "Failed to downsample readings",
+				Detail: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.ReadingBucketList{Buckets: buckets})
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:  "Invalid limit",
+				Detail: err.Error(),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	readings, err := h.repo.ListByRange(sensorID, from, to, limit, order)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to retrieve readings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReadingList{
+		Readings: readings,
+		Count:    len(readings),
+	})
+}
+
+// AggregateReadings returns bucketed rollups of a sensor's readings.
+func (h *ReadingHandler) AggregateReadings(c *gin.Context) {
+	sensorID := c.Param("id")
+
+	bucket := c.DefaultQuery("bucket", "1h")
+	fn := c.DefaultQuery("fn", "avg")
+	from := c.Query("from")
+	to := c.Query("to")
+
+	buckets, err := h.repo.Aggregate(sensorID, from, to, bucket, fn)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Failed to aggregate readings",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReadingBucketList{Buckets: buckets})
+}
@@ -6,18 +6,25 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"iot-sensor-service/events"
+	"iot-sensor-service/middleware"
 	"iot-sensor-service/models"
 	"iot-sensor-service/repositories"
+	"iot-sensor-service/triggers"
 )
 
 // SensorHandler handles sensor CRUD operations.
 type SensorHandler struct {
-	repo repositories.SensorRepository
+	repo      repositories.SensorRepository
+	bus       *events.Bus
+	evaluator *triggers.Evaluator
 }
 
-// NewSensorHandler creates a new sensor handler with the given repository.
-func NewSensorHandler(repo repositories.SensorRepository) *SensorHandler {
-	return &SensorHandler{repo: repo}
+// NewSensorHandler creates a new sensor handler with the given repository,
+// publishing sensor lifecycle events to bus and checking evaluator's
+// triggers after each successful mutation.
+func NewSensorHandler(repo repositories.SensorRepository, bus *events.Bus, evaluator *triggers.Evaluator) *SensorHandler {
+	return &SensorHandler{repo: repo, bus: bus, evaluator: evaluator}
 }
 
 // ListSensors returns all sensors.
@@ -79,6 +86,10 @@ func (h *SensorHandler) CreateSensor(c *gin.Context) {
 		return
 	}
 
+	correlationID := middleware.GetCorrelationID(c)
+	h.bus.Publish(events.SensorCreated, sensor, correlationID)
+	h.evaluator.EvaluateSensor(sensor, correlationID)
+
 	c.JSON(http.StatusCreated, sensor)
 }
 
@@ -112,9 +123,51 @@ func (h *SensorHandler) UpdateSensor(c *gin.Context) {
 		return
 	}
 
+	correlationID := middleware.GetCorrelationID(c)
+	h.bus.Publish(events.SensorUpdated, sensor, correlationID)
+	h.evaluator.EvaluateSensor(sensor, correlationID)
+
 	c.JSON(http.StatusOK, sensor)
 }
 
+// BulkUpsertSensors creates or updates a batch of sensors atomically, one
+// item per row of the request, returning a per-item status instead of
+// aborting the whole batch on the first invalid item.
+func (h *SensorHandler) BulkUpsertSensors(c *gin.Context) {
+	var input models.SensorBulkRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Invalid request body",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	results, err := h.repo.UpsertMany(input.Sensors)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:  "Failed to upsert sensors",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	correlationID := middleware.GetCorrelationID(c)
+	for _, result := range results {
+		switch result.Status {
+		case models.SensorBulkCreated:
+			h.bus.Publish(events.SensorCreated, result.Sensor, correlationID)
+		case models.SensorBulkUpdated:
+			h.bus.Publish(events.SensorUpdated, result.Sensor, correlationID)
+		}
+		if result.Sensor != nil {
+			h.evaluator.EvaluateSensor(result.Sensor, correlationID)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SensorBulkResponse{Results: results})
+}
+
 // DeleteSensor removes a sensor.
 func (h *SensorHandler) DeleteSensor(c *gin.Context) {
 	sensorID := c.Param("id")
@@ -134,5 +187,7 @@ func (h *SensorHandler) DeleteSensor(c *gin.Context) {
 		return
 	}
 
+	h.bus.Publish(events.SensorDeleted, gin.H{"id": sensorID}, middleware.GetCorrelationID(c))
+
 	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/models"
+	"iot-sensor-service/repositories"
+	"iot-sensor-service/triggers"
+)
+
+// TriggerHandler handles trigger registration, listing, and dead-letter replay.
+type TriggerHandler struct {
+	repo       repositories.TriggerRepository
+	sensorRepo repositories.SensorRepository
+	dispatcher *triggers.Dispatcher
+}
+
+// NewTriggerHandler creates a new trigger handler with the given repository
+// and sensor repository, replaying dead-lettered deliveries through dispatcher.
+func NewTriggerHandler(repo repositories.TriggerRepository, sensorRepo repositories.SensorRepository, dispatcher *triggers.Dispatcher) *TriggerHandler {
+	return &TriggerHandler{repo: repo, sensorRepo: sensorRepo, dispatcher: dispatcher}
+}
+
+// CreateTrigger registers a new trigger under a sensor.
+func (h *TriggerHandler) CreateTrigger(c *gin.Context) {
+	sensorID := c.Param("id")
+
+	sensor, err := h.sensorRepo.GetByID(sensorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to retrieve sensor",
+		})
+		return
+	}
+	if sensor == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:  "Sensor not found",
+			Detail: "No sensor with id '" + sensorID + "'",
+		})
+		return
+	}
+
+	var input models.TriggerCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Invalid request body",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	if _, err := triggers.ParseCondition(input.Condition); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Invalid condition",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	trigger, err := h.repo.Create(sensorID, &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Failed to create trigger",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, trigger)
+}
+
+// ListTriggersForSensor returns the triggers registered for a sensor.
+func (h *TriggerHandler) ListTriggersForSensor(c *gin.Context) {
+	sensorID := c.Param("id")
+
+	list, err := h.repo.ListBySensor(sensorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to retrieve triggers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TriggerList{Triggers: list, Count: len(list)})
+}
+
+// ListTriggers returns every trigger, across all sensors.
+func (h *TriggerHandler) ListTriggers(c *gin.Context) {
+	list, err := h.repo.ListAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to retrieve triggers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TriggerList{Triggers: list, Count: len(list)})
+}
+
+// ReplayTrigger re-enqueues every dead-lettered delivery recorded for a
+// trigger, removing each one as it's resubmitted.
+func (h *TriggerHandler) ReplayTrigger(c *gin.Context) {
+	triggerID := c.Param("id")
+
+	trigger, err := h.repo.GetByID(triggerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to retrieve trigger",
+		})
+		return
+	}
+	if trigger == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:  "Trigger not found",
+			Detail: "No trigger with id '" + triggerID + "'",
+		})
+		return
+	}
+
+	deadLetters, err := h.repo.ListDeadLettersForTrigger(triggerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to retrieve dead letters",
+		})
+		return
+	}
+
+	replayed := 0
+	for _, dl := range deadLetters {
+		var payload triggers.WebhookPayload
+		if err := json.Unmarshal([]byte(dl.Payload), &payload); err != nil {
+			continue
+		}
+
+		h.dispatcher.Enqueue(*trigger, payload.Sensor)
+
+		if err := h.repo.DeleteDeadLetter(dl.ID); err != nil {
+			continue
+		}
+		replayed++
+	}
+
+	c.JSON(http.StatusOK, gin.H{"replayed": replayed})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/auth"
+	"iot-sensor-service/models"
+)
+
+// WatcherHandler issues and refreshes JWTs for machine_id/password
+// credentials, mirroring crowdsec LAPI's watcher login flow.
+type WatcherHandler struct {
+	store  auth.WatcherStore
+	jwtCfg *auth.JWTConfig
+}
+
+// NewWatcherHandler creates a new watcher handler backed by store, signing
+// tokens per jwtCfg.
+func NewWatcherHandler(store auth.WatcherStore, jwtCfg *auth.JWTConfig) *WatcherHandler {
+	return &WatcherHandler{store: store, jwtCfg: jwtCfg}
+}
+
+// loginResponse is the envelope returned by both Login and Refresh.
+type loginResponse struct {
+	Code   int    `json:"code"`
+	Expire string `json:"expire"`
+	Token  string `json:"token"`
+}
+
+// Login verifies machine_id + password and issues a new JWT.
+func (h *WatcherHandler) Login(c *gin.Context) {
+	var req auth.WatcherLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Invalid request body",
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	watcher, err := h.store.FindByMachineID(req.MachineID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to look up watcher",
+		})
+		return
+	}
+	if watcher == nil || !auth.Verify(watcher.HashedPassword, req.Password) {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "Invalid machine_id or password",
+		})
+		return
+	}
+
+	token, expiresAt, err := auth.GenerateJWT(h.jwtCfg, watcher.MachineID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to issue token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse{
+		Code:   http.StatusOK,
+		Expire: expiresAt.Format(time.RFC3339),
+		Token:  token,
+	})
+}
+
+// Refresh reissues a token presented in the Authorization header, but only
+// once it is within jwtCfg.RefreshWindow of expiring.
+func (h *WatcherHandler) Refresh(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "Invalid authorization format. Use: Bearer <token>",
+		})
+		return
+	}
+
+	claims, err := auth.ParseJWT(h.jwtCfg, parts[1])
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "Invalid or expired token",
+		})
+		return
+	}
+
+	remaining := claims.ExpiresAt.Time.Sub(time.Now().UTC())
+	if remaining > h.jwtCfg.RefreshWindow {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:  "Token is not yet eligible for refresh",
+			Detail: "refresh is only allowed within " + h.jwtCfg.RefreshWindow.String() + " of expiry",
+		})
+		return
+	}
+
+	token, expiresAt, err := auth.GenerateJWT(h.jwtCfg, claims.MachineID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to issue token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse{
+		Code:   http.StatusOK,
+		Expire: expiresAt.Format(time.RFC3339),
+		Token:  token,
+	})
+}
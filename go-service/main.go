@@ -1,26 +1,35 @@
 /*
 IoT Sensor Service - Go (Gin)
 
-A RESTful API for managing IoT sensor devices with SQLite persistence
-and Bearer token authentication.
+A RESTful API for managing IoT sensor devices with pluggable SQLite/Postgres
+persistence and scoped, rotatable Bearer token authentication.
 */
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 
 	"github.com/gin-gonic/gin"
 
+	"iot-sensor-service/auth"
 	"iot-sensor-service/config"
 	"iot-sensor-service/database"
+	"iot-sensor-service/events"
 	"iot-sensor-service/handlers"
+	"iot-sensor-service/metrics"
 	"iot-sensor-service/middleware"
 	"iot-sensor-service/repositories"
+	"iot-sensor-service/retention"
+	"iot-sensor-service/triggers"
 )
 
 func main() {
+	ctx := context.Background()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -28,8 +37,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Select the database driver (sqlite:// or postgres://) from DATABASE_URL
+	driver, err := database.NewDriver(cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("Failed to configure database driver", "error", err)
+		os.Exit(1)
+	}
+
 	// Connect to database
-	db, err := database.Connect(cfg.DatabasePath)
+	db, err := driver.Connect(ctx)
 	if err != nil {
 		slog.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
@@ -37,23 +53,122 @@ func main() {
 	defer db.Close()
 
 	// Initialize database schema
-	if err := database.InitSchema(db); err != nil {
+	if err := driver.InitSchema(ctx, db); err != nil {
 		slog.Error("Failed to initialize database schema", "error", err)
 		os.Exit(1)
 	}
 
-	// Seed data from JSON if table is empty
-	if err := database.SeedFromJSON(db, cfg.SeedDataPath); err != nil {
-		slog.Error("Failed to seed database", "error", err)
-		os.Exit(1)
+	// Seed data from JSON if table is empty. The seed file's placeholders
+	// are SQLite-specific, so this only runs for local/dev SQLite setups;
+	// Postgres deployments are expected to be provisioned or migrated ahead of time.
+	if driver.Name() == "sqlite" {
+		if err := database.SeedFromJSON(db, cfg.SeedDataPath); err != nil {
+			slog.Error("Failed to seed database", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Create repositories for the selected backend
+	var sensorRepo repositories.SensorRepository
+	var readingRepo repositories.ReadingRepository
+	var tokenStore auth.TokenStore
+	var watcherStore auth.WatcherStore
+	var triggerRepo repositories.TriggerRepository
+	switch driver.Name() {
+	case "postgres":
+		sensorRepo = repositories.NewPostgresSensorRepository(db)
+		readingRepo = repositories.NewPostgresReadingRepository(db)
+		tokenStore = auth.NewPostgresTokenStore(db)
+		watcherStore = auth.NewPostgresWatcherStore(db)
+		// Triggers are not yet implemented against Postgres.
+		triggerRepo = repositories.NewUnsupportedTriggerRepository()
+	default:
+		sensorRepo = repositories.NewSQLiteSensorRepository(db)
+		readingRepo = repositories.NewSQLiteReadingRepository(db)
+		tokenStore = auth.NewSQLiteTokenStore(db)
+		watcherStore = auth.NewSQLiteWatcherStore(db)
+		triggerRepo = repositories.NewSQLiteTriggerRepository(db)
+	}
+
+	// Bootstrap a watcher credential from WATCHER_MACHINE_ID/WATCHER_PASSWORD,
+	// if configured, so operators can log in without a separate admin step.
+	if cfg.WatcherMachineID != "" {
+		hashed, err := auth.HashSecret(cfg.WatcherPassword)
+		if err != nil {
+			slog.Error("Failed to hash watcher password", "error", err)
+			os.Exit(1)
+		}
+		if err := watcherStore.Upsert(cfg.WatcherMachineID, hashed); err != nil {
+			slog.Error("Failed to bootstrap watcher credential", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	jwtCfg := &auth.JWTConfig{
+		Secret:        cfg.JWTSecret,
+		Expiry:        cfg.JWTExpiry,
+		RefreshWindow: cfg.JWTRefreshWindow,
+	}
+
+	// Build the chain of Authenticators AuthMiddleware tries in order. Mode
+	// "both" tries the JWT check first since it can tell at a glance whether
+	// a token is JWT-shaped (see auth.JWTAuth.Authenticate) and falls
+	// through to the static/token-store check otherwise.
+	var authenticators []auth.Authenticator
+	if cfg.AuthMode == "jwt" || cfg.AuthMode == "both" {
+		authenticators = append(authenticators, auth.NewJWTAuth(jwtCfg))
+	}
+	if cfg.AuthMode == "static" || cfg.AuthMode == "both" {
+		authenticators = append(authenticators, auth.NewStaticTokenAuth(tokenStore, cfg.APIToken))
 	}
 
-	// Create repository
-	sensorRepo := repositories.NewSQLiteSensorRepository(db)
+	// Wire mTLS authentication if the server is configured to require or
+	// verify client certificates, independent of AuthMode.
+	if cfg.TLS.MTLSEnabled() {
+		caPool, err := config.LoadCAPool(cfg.TLS.CACertPath)
+		if err != nil {
+			slog.Error("Failed to load mTLS CA bundle", "error", err)
+			os.Exit(1)
+		}
+
+		var crl *auth.CRLChecker
+		if cfg.TLS.CRLPath != "" {
+			crl = auth.NewCRLChecker(cfg.TLS.CRLPath, cfg.TLS.CRLPollInterval)
+			go crl.Run(ctx)
+		}
+
+		authenticators = append([]auth.Authenticator{auth.NewMTLSAuth(caPool, cfg.TLS.IdentityField, crl)}, authenticators...)
+	}
+
+	// Event bus - fans out sensor/reading mutations and trigger firings to
+	// SSE subscribers
+	bus := events.NewBus()
+
+	// Trigger dispatch - evaluates sensor/reading mutations against
+	// registered triggers and delivers matching webhooks in the background.
+	dispatcher := triggers.NewDispatcher(triggerRepo, cfg.TriggerMaxAttempts)
+	dispatcher.Start(ctx, cfg.TriggerWorkers)
+	evaluator := triggers.NewEvaluator(triggerRepo, readingRepo, dispatcher, bus)
 
 	// Create handlers
 	healthHandler := handlers.NewHealthHandler()
-	sensorHandler := handlers.NewSensorHandler(sensorRepo)
+	sensorHandler := handlers.NewSensorHandler(sensorRepo, bus, evaluator)
+	readingHandler := handlers.NewReadingHandler(readingRepo, sensorRepo, bus, evaluator)
+	eventHandler := handlers.NewEventHandler(bus)
+	metricsHandler := handlers.NewMetricsHandler(cfg.MetricsToken)
+	adminTokenHandler := handlers.NewAdminTokenHandler(tokenStore)
+	watcherHandler := handlers.NewWatcherHandler(watcherStore, jwtCfg)
+	triggerHandler := handlers.NewTriggerHandler(triggerRepo, sensorRepo, dispatcher)
+
+	// Periodically refresh the per-sensor gauges in the background until the
+	// process exits.
+	refresher := metrics.NewRefresher(sensorRepo, cfg.MetricsRefreshInterval)
+	go refresher.Run(ctx)
+
+	// Periodically prune raw readings older than the configured retention
+	// window, per sensor type, until the process exits.
+	retentionWorker := retention.NewWorker(readingRepo, cfg.RetentionCheckInterval, cfg.RetentionRawDays, cfg.RetentionRawDaysByType)
+	go retentionWorker.Run(ctx)
 
 	// Set up router
 	gin.SetMode(gin.ReleaseMode)
@@ -62,22 +177,70 @@ func main() {
 	// Add global middleware
 	router.Use(gin.Recovery())
 	router.Use(middleware.LoggingMiddleware())
+	router.Use(middleware.MetricsMiddleware())
 
 	// Health endpoint - unauthenticated for load balancer/orchestrator probes
 	router.GET("/health", healthHandler.Health)
 
-	// Protected routes - require Bearer token authentication
+	// Metrics endpoint - unauthenticated by default, or gated by METRICS_TOKEN
+	// if one is configured, so Prometheus doesn't need the main API token.
+	router.GET("/metrics", metricsHandler.Metrics)
+
+	// Watcher login/refresh - unauthenticated (login) or self-authenticating
+	// via the presented JWT (refresh), independent of AuthMode.
+	router.POST("/v1/watchers/login", watcherHandler.Login)
+	router.POST("/v1/watchers/refresh", watcherHandler.Refresh)
+
+	// Protected routes - require Bearer token authentication, with per-route
+	// scopes enforced on top of the token presented.
 	protected := router.Group("/")
-	protected.Use(middleware.AuthMiddleware(cfg.APIToken))
-	protected.GET("/sensors", sensorHandler.ListSensors)
-	protected.GET("/sensors/:id", sensorHandler.GetSensor)
-	protected.POST("/sensors", sensorHandler.CreateSensor)
-	protected.PUT("/sensors/:id", sensorHandler.UpdateSensor)
-	protected.DELETE("/sensors/:id", sensorHandler.DeleteSensor)
-
-	// Start server
+	protected.Use(middleware.AuthMiddleware(authenticators...))
+	protected.GET("/sensors", middleware.RequireScope(auth.ScopeSensorsRead), sensorHandler.ListSensors)
+	protected.GET("/sensors/:id", middleware.RequireScope(auth.ScopeSensorsRead), sensorHandler.GetSensor)
+	protected.POST("/sensors", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.CreateSensor)
+	protected.POST("/sensors:bulk", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.BulkUpsertSensors)
+	protected.PUT("/sensors/:id", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.UpdateSensor)
+	protected.DELETE("/sensors/:id", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.DeleteSensor)
+	protected.POST("/sensors/:id/readings", middleware.RequireScope(auth.ScopeReadingsIngest), readingHandler.IngestReading)
+	protected.POST("/readings:batch", middleware.RequireScope(auth.ScopeReadingsIngest), readingHandler.IngestBatch)
+	protected.GET("/sensors/:id/readings", middleware.RequireScope(auth.ScopeSensorsRead), readingHandler.ListReadings)
+	protected.GET("/sensors/:id/readings/aggregate", middleware.RequireScope(auth.ScopeSensorsRead), readingHandler.AggregateReadings)
+	protected.GET("/events", middleware.RequireScope(auth.ScopeSensorsRead), eventHandler.Stream)
+	protected.GET("/sensors/events", middleware.RequireScope(auth.ScopeSensorsRead), eventHandler.StreamSensorEvents)
+	protected.GET("/sensors/:id/events", middleware.RequireScope(auth.ScopeSensorsRead), eventHandler.StreamSensorEventsByID)
+	protected.POST("/sensors/:id/triggers", middleware.RequireScope(auth.ScopeTriggersWrite), triggerHandler.CreateTrigger)
+	protected.GET("/sensors/:id/triggers", middleware.RequireScope(auth.ScopeTriggersRead), triggerHandler.ListTriggersForSensor)
+	protected.GET("/triggers", middleware.RequireScope(auth.ScopeTriggersRead), triggerHandler.ListTriggers)
+	protected.POST("/triggers/:id/replay", middleware.RequireScope(auth.ScopeTriggersWrite), triggerHandler.ReplayTrigger)
+
+	// Admin routes - token management, gated by a separate bootstrap root
+	// token so they remain reachable even before any scoped tokens exist.
+	admin := router.Group("/admin")
+	admin.Use(middleware.RootAuthMiddleware(cfg.RootToken))
+	admin.POST("/tokens", adminTokenHandler.CreateToken)
+	admin.GET("/tokens", adminTokenHandler.ListTokens)
+	admin.DELETE("/tokens/:id", adminTokenHandler.RevokeToken)
+
+	// Start server, over HTTPS (with mTLS enforced per cfg.TLS.ClientAuthType)
+	// if a server certificate is configured, or plain HTTP otherwise.
 	addr := fmt.Sprintf(":%d", cfg.Port)
-	slog.Info("Starting Go IoT Sensor Service", "port", cfg.Port)
+	if cfg.TLS.Enabled() {
+		tlsConfig, err := cfg.TLS.GetTLSConfig()
+		if err != nil {
+			slog.Error("Failed to configure TLS", "error", err)
+			os.Exit(1)
+		}
+
+		server := &http.Server{Addr: addr, Handler: router, TLSConfig: tlsConfig}
+		slog.Info("Starting Go IoT Sensor Service", "port", cfg.Port, "tls", true, "client_auth", cfg.TLS.ClientAuthType)
+		if err := server.ListenAndServeTLS("", ""); err != nil {
+			slog.Error("Failed to start server", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	slog.Info("Starting Go IoT Sensor Service", "port", cfg.Port, "tls", false)
 	if err := router.Run(addr); err != nil {
 		slog.Error("Failed to start server", "error", err)
 		os.Exit(1)
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"iot-sensor-service/repositories"
+)
+
+// HTTP request metrics, incremented by middleware.MetricsMiddleware.
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iot_http_requests_total",
+		Help: "Total number of HTTP requests processed, labeled by method, path, and status.",
+	}, []string{"method", "path", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "iot_http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds, labeled by method and path.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path"})
+)
+
+// Domain gauges describing the current state of known sensors, refreshed
+// periodically by Refresher.
+var (
+	SensorValue = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "iot_sensor_value",
+		Help: "Current reported value of a sensor.",
+	}, []string{"id", "name", "type", "location", "unit"})
+
+	SensorStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "iot_sensor_status",
+		Help: "Current status of a sensor (1 if active, 0 otherwise).",
+	}, []string{"id", "status"})
+
+	SensorLastReadingTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "iot_sensor_last_reading_timestamp_seconds",
+		Help: "Unix timestamp of a sensor's last reading.",
+	}, []string{"id"})
+)
+
+// Refresher periodically repopulates the domain gauges from the sensor
+// repository and prunes labels for sensors that have since been deleted.
+type Refresher struct {
+	repo     repositories.SensorRepository
+	interval time.Duration
+	knownIDs map[string]bool
+}
+
+// NewRefresher creates a Refresher that polls repo every interval.
+func NewRefresher(repo repositories.SensorRepository, interval time.Duration) *Refresher {
+	return &Refresher{
+		repo:     repo,
+		interval: interval,
+		knownIDs: make(map[string]bool),
+	}
+}
+
+// Run polls the repository on a ticker until ctx is canceled, updating the
+// domain gauges after each refresh. Intended to be run in its own goroutine.
+func (r *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.refresh()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh()
+		}
+	}
+}
+
+func (r *Refresher) refresh() {
+	sensors, err := r.repo.GetAll()
+	if err != nil {
+		return
+	}
+
+	seenIDs := make(map[string]bool, len(sensors))
+
+	for _, s := range sensors {
+		seenIDs[s.ID] = true
+		r.knownIDs[s.ID] = true
+
+		SensorValue.WithLabelValues(s.ID, s.Name, s.Type, s.Location, s.Unit).Set(s.Value)
+
+		for _, status := range []string{"active", "inactive", "error"} {
+			value := 0.0
+			if s.Status == status {
+				value = 1.0
+			}
+			SensorStatus.WithLabelValues(s.ID, status).Set(value)
+		}
+
+		if ts, err := time.Parse(time.RFC3339, s.LastReading); err == nil {
+			SensorLastReadingTimestamp.WithLabelValues(s.ID).Set(float64(ts.Unix()))
+		}
+	}
+
+	// Prune gauges for sensors that no longer exist.
+	for id := range r.knownIDs {
+		if seenIDs[id] {
+			continue
+		}
+		SensorValue.DeletePartialMatch(prometheus.Labels{"id": id})
+		SensorStatus.DeletePartialMatch(prometheus.Labels{"id": id})
+		SensorLastReadingTimestamp.DeletePartialMatch(prometheus.Labels{"id": id})
+		delete(r.knownIDs, id)
+	}
+}
@@ -2,41 +2,73 @@ package middleware
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/auth"
 )
 
-// AuthMiddleware validates Bearer token authentication.
-func AuthMiddleware(validToken string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
+// ScopesKey is the context key under which the authenticated request's
+// scopes are stored by AuthMiddleware.
+const ScopesKey = "token_scopes"
 
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Authorization header required",
-			})
-			return
-		}
+// MachineIDKey is the context key under which an authenticated caller's
+// machine identity (from a JWT watcher login or an mTLS client certificate)
+// is stored by AuthMiddleware.
+const MachineIDKey = "machine_id"
 
-		// Parse "Bearer <token>" format
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+// AuthMiddleware tries each authenticator in order and accepts the request
+// on the first one that succeeds, storing its Identity on the Gin context.
+// An authenticator returning auth.ErrNoCredential (the request didn't
+// present that kind of credential at all) falls through to the next one;
+// any other error - a credential that was present but invalid - rejects the
+// request immediately rather than trying the rest. If none succeed, or none
+// are configured, the request is rejected with 401.
+func AuthMiddleware(authenticators ...auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, a := range authenticators {
+			identity, err := a.Authenticate(c)
+			if err == nil {
+				c.Set(ScopesKey, identity.Scopes)
+				if identity.MachineID != "" {
+					c.Set(MachineIDKey, identity.MachineID)
+				}
+				c.Next()
+				return
+			}
+			if err == auth.ErrNoCredential {
+				continue
+			}
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization format. Use: Bearer <token>",
+				"error": "Invalid or expired token",
 			})
 			return
 		}
 
-		token := parts[1]
-		if token != validToken {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid or expired token",
-			})
-			return
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "Authorization required",
+		})
+	}
+}
+
+// GetScopes retrieves the authenticated request's scopes from the Gin
+// context.
+func GetScopes(c *gin.Context) []string {
+	if v, exists := c.Get(ScopesKey); exists {
+		if scopes, ok := v.([]string); ok {
+			return scopes
 		}
+	}
+	return nil
+}
 
-		// Token is valid, continue to next handler
-		c.Next()
+// GetMachineID retrieves the authenticated caller's machine identity from
+// the Gin context, if the request was authenticated via JWT or mTLS.
+func GetMachineID(c *gin.Context) string {
+	if v, exists := c.Get(MachineIDKey); exists {
+		if machineID, ok := v.(string); ok {
+			return machineID
+		}
 	}
+	return ""
 }
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RootAuthMiddleware gates admin endpoints (token management) behind a
+// single bootstrap root token, independent of the regular API token store -
+// this keeps token administration usable even before any API tokens exist.
+func RootAuthMiddleware(rootToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" || parts[1] != rootToken {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or missing root token",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
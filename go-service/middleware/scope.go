@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/auth"
+)
+
+// RequireScope aborts the request with 403 Forbidden unless the token
+// authenticated by AuthMiddleware carries the given scope (or the wildcard
+// scope granted in legacy single-token mode). Must run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, s := range GetScopes(c) {
+			if s == scope || s == auth.ScopeWildcard {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "Token is missing required scope: " + scope,
+		})
+	}
+}
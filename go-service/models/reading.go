@@ -0,0 +1,75 @@
+package models
+
+import "fmt"
+
+// ValidAggregateFuncs contains all allowed aggregate functions for bucketed rollups.
+var ValidAggregateFuncs = map[string]bool{
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+	"sum":   true,
+	"count": true,
+}
+
+// Reading represents a single measured value recorded for a sensor.
+type Reading struct {
+	ID       int64   `json:"id"`
+	SensorID string  `json:"sensor_id"`
+	Value    float64 `json:"value"`
+	Unit     string  `json:"unit"`
+	TakenAt  string  `json:"taken_at"`
+}
+
+// ReadingCreate represents the request body for ingesting a single reading.
+type ReadingCreate struct {
+	Value   float64 `json:"value"`
+	Unit    string  `json:"unit" binding:"required,min=1,max=50"`
+	TakenAt string  `json:"taken_at,omitempty"`
+}
+
+// Validate checks if the ReadingCreate fields are valid.
+func (r *ReadingCreate) Validate() error {
+	if r.Unit == "" {
+		return fmt.Errorf("unit is required")
+	}
+	return nil
+}
+
+// ReadingBatchCreate represents the request body for ingesting multiple readings at once.
+type ReadingBatchCreate struct {
+	Readings []ReadingCreate `json:"readings" binding:"required,min=1,dive"`
+}
+
+// ReadingBatchItem represents one reading within a cross-sensor batch,
+// carrying its own sensor id.
+type ReadingBatchItem struct {
+	SensorID string  `json:"sensor_id" binding:"required"`
+	Value    float64 `json:"value"`
+	Unit     string  `json:"unit" binding:"required,min=1,max=50"`
+	TakenAt  string  `json:"taken_at,omitempty"`
+}
+
+// ReadingCrossBatchCreate represents the request body for POST
+// /readings:batch, ingesting readings for multiple sensors in one
+// transaction.
+type ReadingCrossBatchCreate struct {
+	Readings []ReadingBatchItem `json:"readings" binding:"required,min=1,dive"`
+}
+
+// ReadingList represents the response for listing readings.
+type ReadingList struct {
+	Readings []Reading `json:"readings"`
+	Count    int       `json:"count"`
+}
+
+// ReadingBucket represents one bucketed rollup produced by an aggregate query.
+type ReadingBucket struct {
+	Bucket string  `json:"bucket"`
+	Value  float64 `json:"value"`
+	Count  int     `json:"count"`
+}
+
+// ReadingBucketList represents the response for an aggregate readings query.
+type ReadingBucketList struct {
+	Buckets []ReadingBucket `json:"buckets"`
+}
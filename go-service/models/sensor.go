@@ -87,6 +87,60 @@ type SensorList struct {
 	Count   int      `json:"count"`
 }
 
+// SensorUpsertItem represents a single sensor in a bulk create-or-update
+// request. Unlike SensorCreate, the ID is caller-supplied so a previously
+// synced sensor can be matched and updated in place.
+type SensorUpsertItem struct {
+	ID       string  `json:"id" binding:"required"`
+	Name     string  `json:"name" binding:"required,min=1,max=100"`
+	Type     string  `json:"type" binding:"required"`
+	Location string  `json:"location" binding:"required,min=1,max=100"`
+	Value    float64 `json:"value"`
+	Unit     string  `json:"unit" binding:"required,min=1,max=50"`
+	Status   string  `json:"status" binding:"required"`
+}
+
+// Validate checks if the SensorUpsertItem fields are valid.
+func (s *SensorUpsertItem) Validate() error {
+	if !ValidSensorTypes[s.Type] {
+		return fmt.Errorf("invalid sensor type: %s", s.Type)
+	}
+	if !ValidSensorStatuses[s.Status] {
+		return fmt.Errorf("invalid sensor status: %s", s.Status)
+	}
+	return nil
+}
+
+// SensorBulkRequest represents the request body for a bulk create-or-update.
+type SensorBulkRequest struct {
+	Sensors []SensorUpsertItem `json:"sensors" binding:"required,min=1,dive"`
+}
+
+// SensorBulkStatus describes the outcome of a single item in a bulk upsert.
+type SensorBulkStatus string
+
+const (
+	SensorBulkCreated SensorBulkStatus = "created"
+	SensorBulkUpdated SensorBulkStatus = "updated"
+	SensorBulkError   SensorBulkStatus = "error"
+)
+
+// SensorBulkResult reports the outcome of one item from a bulk upsert,
+// keyed by its position in the request so a partial failure doesn't need to
+// abort the rest of the batch.
+type SensorBulkResult struct {
+	Index  int              `json:"index"`
+	ID     string           `json:"id,omitempty"`
+	Status SensorBulkStatus `json:"status"`
+	Sensor *Sensor          `json:"sensor,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// SensorBulkResponse represents the response for a bulk create-or-update.
+type SensorBulkResponse struct {
+	Results []SensorBulkResult `json:"results"`
+}
+
 // HealthResponse represents the health check response.
 type HealthResponse struct {
 	Status  string `json:"status"`
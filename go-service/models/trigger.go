@@ -0,0 +1,43 @@
+package models
+
+// Trigger represents a registered rule that fires a webhook whenever a
+// sensor update matches its condition (e.g. "value > 80", "status ==
+// \"inactive\"", "delta > 5 over 60s"); see the triggers package for how
+// conditions are parsed and evaluated.
+type Trigger struct {
+	ID         string `json:"id"`
+	SensorID   string `json:"sensor_id"`
+	Condition  string `json:"condition"`
+	WebhookURL string `json:"webhook_url"`
+	Secret     string `json:"-"`
+	Active     bool   `json:"active"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// TriggerCreate represents the request body for registering a new trigger
+// under a sensor.
+type TriggerCreate struct {
+	Condition  string `json:"condition" binding:"required"`
+	WebhookURL string `json:"webhook_url" binding:"required,url"`
+	Secret     string `json:"secret" binding:"required,min=8"`
+}
+
+// TriggerList represents the response for listing triggers.
+type TriggerList struct {
+	Triggers []Trigger `json:"triggers"`
+	Count    int       `json:"count"`
+}
+
+// DeadLetter represents a trigger delivery that exhausted its retry budget
+// without a successful webhook response, retained for inspection and
+// manual replay via POST /triggers/:id/replay.
+type DeadLetter struct {
+	ID            string `json:"id"`
+	TriggerID     string `json:"trigger_id"`
+	CorrelationID string `json:"correlation_id"`
+	Payload       string `json:"payload"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error"`
+	CreatedAt     string `json:"created_at"`
+}
@@ -0,0 +1,298 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"iot-sensor-service/models"
+)
+
+// PostgresReadingRepository implements ReadingRepository using Postgres.
+type PostgresReadingRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresReadingRepository creates a new Postgres-backed reading repository.
+func NewPostgresReadingRepository(db *sql.DB) *PostgresReadingRepository {
+	return &PostgresReadingRepository{db: db}
+}
+
+// Create ingests a single reading for a sensor and updates the sensor's
+// current value/last_reading in the same transaction.
+func (r *PostgresReadingRepository) Create(sensorID string, reading *models.ReadingCreate) (*models.Reading, error) {
+	readings, err := r.CreateMany(sensorID, []models.ReadingCreate{*reading})
+	if err != nil {
+		return nil, err
+	}
+	return &readings[0], nil
+}
+
+// CreateMany ingests a batch of readings for a sensor in a single
+// transaction, updating the sensor's value/last_reading to the latest one.
+func (r *PostgresReadingRepository) CreateMany(sensorID string, readings []models.ReadingCreate) ([]models.Reading, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM sensors WHERE id = $1", sensorID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO readings (sensor_id, value, unit, taken_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	created := make([]models.Reading, 0, len(readings))
+	var last models.ReadingCreate
+
+	for _, rc := range readings {
+		if err := rc.Validate(); err != nil {
+			return nil, err
+		}
+
+		takenAt := rc.TakenAt
+		if takenAt == "" {
+			takenAt = models.Now()
+		}
+
+		var id int64
+		if err := stmt.QueryRow(sensorID, rc.Value, rc.Unit, takenAt).Scan(&id); err != nil {
+			return nil, err
+		}
+
+		created = append(created, models.Reading{
+			ID:       id,
+			SensorID: sensorID,
+			Value:    rc.Value,
+			Unit:     rc.Unit,
+			TakenAt:  takenAt,
+		})
+		last = rc
+	}
+
+	// Update the parent sensor to reflect the most recently ingested reading.
+	lastTakenAt := created[len(created)-1].TakenAt
+	_, err = tx.Exec(`
+		UPDATE sensors SET value = $1, unit = $2, last_reading = $3, updated_at = $4
+		WHERE id = $5
+	`, last.Value, last.Unit, lastTakenAt, models.Now(), sensorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// CreateBatch ingests readings for potentially different sensors in a
+// single transaction. Each affected sensor's value/last_reading is updated
+// to the last reading ingested for it, in request order.
+func (r *PostgresReadingRepository) CreateBatch(items []models.ReadingBatchItem) ([]models.Reading, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO readings (sensor_id, value, unit, taken_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	created := make([]models.Reading, 0, len(items))
+	latest := make(map[string]models.ReadingBatchItem, len(items))
+
+	for _, item := range items {
+		var exists int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM sensors WHERE id = $1", item.SensorID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			return nil, fmt.Errorf("sensor not found: %s", item.SensorID)
+		}
+
+		takenAt := item.TakenAt
+		if takenAt == "" {
+			takenAt = models.Now()
+		}
+
+		var id int64
+		if err := stmt.QueryRow(item.SensorID, item.Value, item.Unit, takenAt).Scan(&id); err != nil {
+			return nil, err
+		}
+
+		created = append(created, models.Reading{
+			ID:       id,
+			SensorID: item.SensorID,
+			Value:    item.Value,
+			Unit:     item.Unit,
+			TakenAt:  takenAt,
+		})
+		item.TakenAt = takenAt
+		latest[item.SensorID] = item
+	}
+
+	now := models.Now()
+	for sensorID, item := range latest {
+		if _, err := tx.Exec(`
+			UPDATE sensors SET value = $1, unit = $2, last_reading = $3, updated_at = $4
+			WHERE id = $5
+		`, item.Value, item.Unit, item.TakenAt, now, sensorID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListByRange retrieves readings for a sensor within an optional time range.
+func (r *PostgresReadingRepository) ListByRange(sensorID, from, to string, limit int, order string) ([]models.Reading, error) {
+	if order != "asc" && order != "desc" {
+		order = "asc"
+	}
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	query := "SELECT id, sensor_id, value, unit, taken_at FROM readings WHERE sensor_id = $1"
+	args := []interface{}{sensorID}
+
+	if from != "" {
+		args = append(args, from)
+		query += fmt.Sprintf(" AND taken_at >= $%d", len(args))
+	}
+	if to != "" {
+		args = append(args, to)
+		query += fmt.Sprintf(" AND taken_at <= $%d", len(args))
+	}
+
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY taken_at %s LIMIT $%d", order, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var readings []models.Reading
+	for rows.Next() {
+		var rd models.Reading
+		if err := rows.Scan(&rd.ID, &rd.SensorID, &rd.Value, &rd.Unit, &rd.TakenAt); err != nil {
+			return nil, err
+		}
+		readings = append(readings, rd)
+	}
+
+	if readings == nil {
+		readings = []models.Reading{}
+	}
+
+	return readings, rows.Err()
+}
+
+// DeleteOlderThan removes raw readings taken before cutoff for every sensor
+// of sensorType, returning the number of rows removed.
+func (r *PostgresReadingRepository) DeleteOlderThan(sensorType, cutoff string) (int64, error) {
+	result, err := r.db.Exec(`
+		DELETE FROM readings
+		WHERE taken_at < $1
+		AND sensor_id IN (SELECT id FROM sensors WHERE type = $2)
+	`, cutoff, sensorType)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// postgresBucketExpr builds the SQL expression that floors a reading's
+// taken_at to the start of its bucket width, by truncating the Unix epoch
+// to a multiple of the bucket's duration. date_trunc only aligns to the
+// unit it names, so widths like "5m"/"15m" are floored in integer seconds
+// instead. bucketWidthSeconds is shared with the SQLite repository.
+func postgresBucketExpr(bucket string) (string, error) {
+	width, err := bucketWidthSeconds(bucket)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		`to_char(to_timestamp(floor(extract(epoch from taken_at::timestamptz) / %d) * %d) AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`,
+		width, width,
+	), nil
+}
+
+// Aggregate produces bucketed rollups of readings for a sensor, computed in SQL.
+func (r *PostgresReadingRepository) Aggregate(sensorID, from, to, bucket, fn string) ([]models.ReadingBucket, error) {
+	bucketCol, err := postgresBucketExpr(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlFn, err := aggregateSQLFunc(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s AS bucket, %s AS value, COUNT(*) AS count
+		FROM readings
+		WHERE sensor_id = $1
+	`, bucketCol, sqlFn)
+	args := []interface{}{sensorID}
+
+	if from != "" {
+		args = append(args, from)
+		query += fmt.Sprintf(" AND taken_at >= $%d", len(args))
+	}
+	if to != "" {
+		args = append(args, to)
+		query += fmt.Sprintf(" AND taken_at <= $%d", len(args))
+	}
+
+	query += " GROUP BY bucket ORDER BY bucket ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []models.ReadingBucket
+	for rows.Next() {
+		var b models.ReadingBucket
+		if err := rows.Scan(&b.Bucket, &b.Value, &b.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	if buckets == nil {
+		buckets = []models.ReadingBucket{}
+	}
+
+	return buckets, rows.Err()
+}
@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"iot-sensor-service/models"
+)
+
+// PostgresSensorRepository implements SensorRepository using Postgres.
+type PostgresSensorRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSensorRepository creates a new Postgres-backed sensor repository.
+func NewPostgresSensorRepository(db *sql.DB) *PostgresSensorRepository {
+	return &PostgresSensorRepository{db: db}
+}
+
+// GetAll retrieves all sensors from the database.
+func (r *PostgresSensorRepository) GetAll() ([]models.Sensor, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, type, location, value, unit, status, last_reading, created_at, updated_at
+		FROM sensors ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sensors []models.Sensor
+	for rows.Next() {
+		var s models.Sensor
+		err := rows.Scan(&s.ID, &s.Name, &s.Type, &s.Location, &s.Value, &s.Unit, &s.Status, &s.LastReading, &s.CreatedAt, &s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		sensors = append(sensors, s)
+	}
+
+	if sensors == nil {
+		sensors = []models.Sensor{}
+	}
+
+	return sensors, rows.Err()
+}
+
+// GetByID retrieves a sensor by its ID.
+func (r *PostgresSensorRepository) GetByID(id string) (*models.Sensor, error) {
+	var s models.Sensor
+	err := r.db.QueryRow(`
+		SELECT id, name, type, location, value, unit, status, last_reading, created_at, updated_at
+		FROM sensors WHERE id = $1
+	`, id).Scan(&s.ID, &s.Name, &s.Type, &s.Location, &s.Value, &s.Unit, &s.Status, &s.LastReading, &s.CreatedAt, &s.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Create inserts a new sensor into the database.
+func (r *PostgresSensorRepository) Create(sensor *models.SensorCreate) (*models.Sensor, error) {
+	// Validate input
+	if err := sensor.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Generate new ID
+	var maxNum sql.NullInt64
+	err := r.db.QueryRow("SELECT MAX(SUBSTRING(id FROM 8)::INTEGER) FROM sensors WHERE id LIKE 'sensor-%'").Scan(&maxNum)
+	if err != nil {
+		return nil, err
+	}
+
+	nextNum := int64(1)
+	if maxNum.Valid {
+		nextNum = maxNum.Int64 + 1
+	}
+	newID := fmt.Sprintf("sensor-%03d", nextNum)
+
+	now := models.Now()
+
+	_, err = r.db.Exec(`
+		INSERT INTO sensors (id, name, type, location, value, unit, status, last_reading, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, newID, sensor.Name, sensor.Type, sensor.Location, sensor.Value, sensor.Unit, sensor.Status, now, now, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the sensor's initial value as its first reading, so history
+	// (see ReadingRepository) reflects it from creation onward.
+	if _, err := r.db.Exec(`
+		INSERT INTO readings (sensor_id, value, unit, taken_at)
+		VALUES ($1, $2, $3, $4)
+	`, newID, sensor.Value, sensor.Unit, now); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(newID)
+}
+
+// Update modifies an existing sensor.
+func (r *PostgresSensorRepository) Update(id string, updates *models.SensorUpdate) (*models.Sensor, error) {
+	// Check if sensor exists
+	existing, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	// Validate input
+	if err := updates.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Build update query
+	query := "UPDATE sensors SET updated_at = $1, last_reading = $2"
+	args := []interface{}{models.Now(), models.Now()}
+
+	if updates.Name != nil {
+		args = append(args, *updates.Name)
+		query += fmt.Sprintf(", name = $%d", len(args))
+	}
+	if updates.Type != nil {
+		args = append(args, *updates.Type)
+		query += fmt.Sprintf(", type = $%d", len(args))
+	}
+	if updates.Location != nil {
+		args = append(args, *updates.Location)
+		query += fmt.Sprintf(", location = $%d", len(args))
+	}
+	if updates.Value != nil {
+		args = append(args, *updates.Value)
+		query += fmt.Sprintf(", value = $%d", len(args))
+	}
+	if updates.Unit != nil {
+		args = append(args, *updates.Unit)
+		query += fmt.Sprintf(", unit = $%d", len(args))
+	}
+	if updates.Status != nil {
+		args = append(args, *updates.Status)
+		query += fmt.Sprintf(", status = $%d", len(args))
+	}
+
+	args = append(args, id)
+	query += fmt.Sprintf(" WHERE id = $%d", len(args))
+
+	_, err = r.db.Exec(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the new value as a reading, so history (see ReadingRepository)
+	// captures updates made through the sensor API, not just explicit ingest.
+	if updates.Value != nil {
+		unit := existing.Unit
+		if updates.Unit != nil {
+			unit = *updates.Unit
+		}
+		if _, err := r.db.Exec(`
+			INSERT INTO readings (sensor_id, value, unit, taken_at)
+			VALUES ($1, $2, $3, $4)
+		`, id, *updates.Value, unit, models.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.GetByID(id)
+}
+
+// Delete removes a sensor from the database.
+func (r *PostgresSensorRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM sensors WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpsertMany inserts or updates a batch of sensors in a single transaction.
+// Each item is applied under its own savepoint so a single invalid or
+// failing item rolls back on its own without aborting the rest of the batch.
+func (r *PostgresSensorRepository) UpsertMany(items []models.SensorUpsertItem) ([]models.SensorBulkResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]models.SensorBulkResult, len(items))
+
+	for i, item := range items {
+		savepoint := fmt.Sprintf("bulk_upsert_%d", i)
+		if _, err := tx.Exec("SAVEPOINT " + savepoint); err != nil {
+			return nil, err
+		}
+
+		sensor, created, err := postgresUpsertOne(tx, &item)
+		if err != nil {
+			if _, rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint); rbErr != nil {
+				return nil, rbErr
+			}
+			results[i] = models.SensorBulkResult{Index: i, ID: item.ID, Status: models.SensorBulkError, Error: err.Error()}
+			continue
+		}
+		if _, err := tx.Exec("RELEASE SAVEPOINT " + savepoint); err != nil {
+			return nil, err
+		}
+
+		status := models.SensorBulkUpdated
+		if created {
+			status = models.SensorBulkCreated
+		}
+		results[i] = models.SensorBulkResult{Index: i, ID: sensor.ID, Status: status, Sensor: sensor}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// postgresUpsertOne inserts or updates a single sensor within tx, reporting
+// whether the row was newly created.
+func postgresUpsertOne(tx *sql.Tx, item *models.SensorUpsertItem) (*models.Sensor, bool, error) {
+	if err := item.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	var existed int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM sensors WHERE id = $1", item.ID).Scan(&existed); err != nil {
+		return nil, false, err
+	}
+
+	now := models.Now()
+	_, err := tx.Exec(`
+		INSERT INTO sensors (id, name, type, location, value, unit, status, last_reading, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			type = excluded.type,
+			location = excluded.location,
+			value = excluded.value,
+			unit = excluded.unit,
+			status = excluded.status,
+			last_reading = excluded.last_reading,
+			updated_at = excluded.updated_at
+	`, item.ID, item.Name, item.Type, item.Location, item.Value, item.Unit, item.Status, now, now, now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var s models.Sensor
+	err = tx.QueryRow(`
+		SELECT id, name, type, location, value, unit, status, last_reading, created_at, updated_at
+		FROM sensors WHERE id = $1
+	`, item.ID).Scan(&s.ID, &s.Name, &s.Type, &s.Location, &s.Value, &s.Unit, &s.Status, &s.LastReading, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &s, existed == 0, nil
+}
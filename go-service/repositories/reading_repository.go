@@ -0,0 +1,359 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"iot-sensor-service/models"
+)
+
+// ReadingRepository defines the interface for sensor reading data access.
+type ReadingRepository interface {
+	Create(sensorID string, reading *models.ReadingCreate) (*models.Reading, error)
+	CreateMany(sensorID string, readings []models.ReadingCreate) ([]models.Reading, error)
+	// CreateBatch ingests readings for potentially different sensors in a
+	// single transaction, updating each affected sensor's value/last_reading
+	// to its latest ingested reading.
+	CreateBatch(items []models.ReadingBatchItem) ([]models.Reading, error)
+	ListByRange(sensorID, from, to string, limit int, order string) ([]models.Reading, error)
+	Aggregate(sensorID, from, to, bucket, fn string) ([]models.ReadingBucket, error)
+	// DeleteOlderThan removes raw readings taken before cutoff for every
+	// sensor of sensorType, for use by a retention worker. Returns the
+	// number of rows removed.
+	DeleteOlderThan(sensorType, cutoff string) (int64, error)
+}
+
+// SQLiteReadingRepository implements ReadingRepository using SQLite.
+type SQLiteReadingRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteReadingRepository creates a new SQLite-backed reading repository.
+func NewSQLiteReadingRepository(db *sql.DB) *SQLiteReadingRepository {
+	return &SQLiteReadingRepository{db: db}
+}
+
+// Create ingests a single reading for a sensor and updates the sensor's
+// current value/last_reading in the same transaction.
+func (r *SQLiteReadingRepository) Create(sensorID string, reading *models.ReadingCreate) (*models.Reading, error) {
+	readings, err := r.CreateMany(sensorID, []models.ReadingCreate{*reading})
+	if err != nil {
+		return nil, err
+	}
+	return &readings[0], nil
+}
+
+// CreateMany ingests a batch of readings for a sensor in a single
+// transaction, updating the sensor's value/last_reading to the latest one.
+func (r *SQLiteReadingRepository) CreateMany(sensorID string, readings []models.ReadingCreate) ([]models.Reading, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM sensors WHERE id = ?", sensorID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO readings (sensor_id, value, unit, taken_at)
+		VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	created := make([]models.Reading, 0, len(readings))
+	var last models.ReadingCreate
+
+	for _, rc := range readings {
+		if err := rc.Validate(); err != nil {
+			return nil, err
+		}
+
+		takenAt := rc.TakenAt
+		if takenAt == "" {
+			takenAt = models.Now()
+		}
+
+		res, err := stmt.Exec(sensorID, rc.Value, rc.Unit, takenAt)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+
+		created = append(created, models.Reading{
+			ID:       id,
+			SensorID: sensorID,
+			Value:    rc.Value,
+			Unit:     rc.Unit,
+			TakenAt:  takenAt,
+		})
+		last = rc
+	}
+
+	// Update the parent sensor to reflect the most recently ingested reading.
+	lastTakenAt := created[len(created)-1].TakenAt
+	_, err = tx.Exec(`
+		UPDATE sensors SET value = ?, unit = ?, last_reading = ?, updated_at = ?
+		WHERE id = ?
+	`, last.Value, last.Unit, lastTakenAt, models.Now(), sensorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// CreateBatch ingests readings for potentially different sensors in a
+// single transaction. Each affected sensor's value/last_reading is updated
+// to the last reading ingested for it, in request order.
+func (r *SQLiteReadingRepository) CreateBatch(items []models.ReadingBatchItem) ([]models.Reading, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO readings (sensor_id, value, unit, taken_at)
+		VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	created := make([]models.Reading, 0, len(items))
+	latest := make(map[string]models.ReadingBatchItem, len(items))
+
+	for _, item := range items {
+		var exists int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM sensors WHERE id = ?", item.SensorID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			return nil, fmt.Errorf("sensor not found: %s", item.SensorID)
+		}
+
+		takenAt := item.TakenAt
+		if takenAt == "" {
+			takenAt = models.Now()
+		}
+
+		res, err := stmt.Exec(item.SensorID, item.Value, item.Unit, takenAt)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+
+		created = append(created, models.Reading{
+			ID:       id,
+			SensorID: item.SensorID,
+			Value:    item.Value,
+			Unit:     item.Unit,
+			TakenAt:  takenAt,
+		})
+		item.TakenAt = takenAt
+		latest[item.SensorID] = item
+	}
+
+	now := models.Now()
+	for sensorID, item := range latest {
+		if _, err := tx.Exec(`
+			UPDATE sensors SET value = ?, unit = ?, last_reading = ?, updated_at = ?
+			WHERE id = ?
+		`, item.Value, item.Unit, item.TakenAt, now, sensorID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListByRange retrieves readings for a sensor within an optional time range.
+func (r *SQLiteReadingRepository) ListByRange(sensorID, from, to string, limit int, order string) ([]models.Reading, error) {
+	if order != "asc" && order != "desc" {
+		order = "asc"
+	}
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	query := "SELECT id, sensor_id, value, unit, taken_at FROM readings WHERE sensor_id = ?"
+	args := []interface{}{sensorID}
+
+	if from != "" {
+		query += " AND taken_at >= ?"
+		args = append(args, from)
+	}
+	if to != "" {
+		query += " AND taken_at <= ?"
+		args = append(args, to)
+	}
+
+	query += fmt.Sprintf(" ORDER BY taken_at %s LIMIT ?", order)
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var readings []models.Reading
+	for rows.Next() {
+		var rd models.Reading
+		if err := rows.Scan(&rd.ID, &rd.SensorID, &rd.Value, &rd.Unit, &rd.TakenAt); err != nil {
+			return nil, err
+		}
+		readings = append(readings, rd)
+	}
+
+	if readings == nil {
+		readings = []models.Reading{}
+	}
+
+	return readings, rows.Err()
+}
+
+// DeleteOlderThan removes raw readings taken before cutoff for every sensor
+// of sensorType, returning the number of rows removed.
+func (r *SQLiteReadingRepository) DeleteOlderThan(sensorType, cutoff string) (int64, error) {
+	result, err := r.db.Exec(`
+		DELETE FROM readings
+		WHERE taken_at < ?
+		AND sensor_id IN (SELECT id FROM sensors WHERE type = ?)
+	`, cutoff, sensorType)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// bucketWidthSeconds maps a bucket width (e.g. "1h", "1d", "15m") to its
+// duration in seconds, used to floor reading timestamps into rollup periods.
+func bucketWidthSeconds(bucket string) (int, error) {
+	switch bucket {
+	case "1m":
+		return 60, nil
+	case "5m":
+		return 300, nil
+	case "15m":
+		return 900, nil
+	case "1h":
+		return 3600, nil
+	case "1d":
+		return 86400, nil
+	default:
+		return 0, fmt.Errorf("invalid bucket: %s", bucket)
+	}
+}
+
+// bucketExpr builds the SQL expression that floors a reading's taken_at to
+// the start of its bucket width, by truncating the Unix epoch to a multiple
+// of the bucket's duration. strftime's format verbs can't express widths
+// like "5m"/"15m" directly (they only align to the units they name), so the
+// flooring is done in integer seconds rather than in the formatted string.
+func bucketExpr(bucket string) (string, error) {
+	width, err := bucketWidthSeconds(bucket)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', (CAST(strftime('%%s', taken_at) AS INTEGER) / %d) * %d, 'unixepoch')",
+		width, width,
+	), nil
+}
+
+// aggregateSQLFunc maps an aggregate function name to its SQL equivalent.
+func aggregateSQLFunc(fn string) (string, error) {
+	switch fn {
+	case "avg":
+		return "AVG(value)", nil
+	case "min":
+		return "MIN(value)", nil
+	case "max":
+		return "MAX(value)", nil
+	case "sum":
+		return "SUM(value)", nil
+	case "count":
+		return "COUNT(*)", nil
+	default:
+		return "", fmt.Errorf("invalid aggregate function: %s", fn)
+	}
+}
+
+// Aggregate produces bucketed rollups of readings for a sensor, computed in SQL.
+func (r *SQLiteReadingRepository) Aggregate(sensorID, from, to, bucket, fn string) ([]models.ReadingBucket, error) {
+	bucketCol, err := bucketExpr(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlFn, err := aggregateSQLFunc(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s AS bucket, %s AS value, COUNT(*) AS count
+		FROM readings
+		WHERE sensor_id = ?
+	`, bucketCol, sqlFn)
+	args := []interface{}{sensorID}
+
+	if from != "" {
+		query += " AND taken_at >= ?"
+		args = append(args, from)
+	}
+	if to != "" {
+		query += " AND taken_at <= ?"
+		args = append(args, to)
+	}
+
+	query += " GROUP BY bucket ORDER BY bucket ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []models.ReadingBucket
+	for rows.Next() {
+		var b models.ReadingBucket
+		if err := rows.Scan(&b.Bucket, &b.Value, &b.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	if buckets == nil {
+		buckets = []models.ReadingBucket{}
+	}
+
+	return buckets, rows.Err()
+}
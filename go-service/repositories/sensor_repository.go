@@ -14,6 +14,7 @@ type SensorRepository interface {
 	Create(sensor *models.SensorCreate) (*models.Sensor, error)
 	Update(id string, updates *models.SensorUpdate) (*models.Sensor, error)
 	Delete(id string) error
+	UpsertMany(items []models.SensorUpsertItem) ([]models.SensorBulkResult, error)
 }
 
 // SQLiteSensorRepository implements SensorRepository using SQLite.
@@ -102,6 +103,15 @@ func (r *SQLiteSensorRepository) Create(sensor *models.SensorCreate) (*models.Se
 		return nil, err
 	}
 
+	// Record the sensor's initial value as its first reading, so history
+	// (see ReadingRepository) reflects it from creation onward.
+	if _, err := r.db.Exec(`
+		INSERT INTO readings (sensor_id, value, unit, taken_at)
+		VALUES (?, ?, ?, ?)
+	`, newID, sensor.Value, sensor.Unit, now); err != nil {
+		return nil, err
+	}
+
 	return r.GetByID(newID)
 }
 
@@ -158,6 +168,21 @@ func (r *SQLiteSensorRepository) Update(id string, updates *models.SensorUpdate)
 		return nil, err
 	}
 
+	// Record the new value as a reading, so history (see ReadingRepository)
+	// captures updates made through the sensor API, not just explicit ingest.
+	if updates.Value != nil {
+		unit := existing.Unit
+		if updates.Unit != nil {
+			unit = *updates.Unit
+		}
+		if _, err := r.db.Exec(`
+			INSERT INTO readings (sensor_id, value, unit, taken_at)
+			VALUES (?, ?, ?, ?)
+		`, id, *updates.Value, unit, models.Now()); err != nil {
+			return nil, err
+		}
+	}
+
 	return r.GetByID(id)
 }
 
@@ -179,3 +204,89 @@ func (r *SQLiteSensorRepository) Delete(id string) error {
 
 	return nil
 }
+
+// UpsertMany inserts or updates a batch of sensors in a single transaction.
+// Each item is applied under its own savepoint so a single invalid or
+// failing item rolls back on its own without aborting the rest of the batch.
+func (r *SQLiteSensorRepository) UpsertMany(items []models.SensorUpsertItem) ([]models.SensorBulkResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]models.SensorBulkResult, len(items))
+
+	for i, item := range items {
+		savepoint := fmt.Sprintf("bulk_upsert_%d", i)
+		if _, err := tx.Exec("SAVEPOINT " + savepoint); err != nil {
+			return nil, err
+		}
+
+		sensor, created, err := sqliteUpsertOne(tx, &item)
+		if err != nil {
+			if _, rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint); rbErr != nil {
+				return nil, rbErr
+			}
+			results[i] = models.SensorBulkResult{Index: i, ID: item.ID, Status: models.SensorBulkError, Error: err.Error()}
+			continue
+		}
+		if _, err := tx.Exec("RELEASE SAVEPOINT " + savepoint); err != nil {
+			return nil, err
+		}
+
+		status := models.SensorBulkUpdated
+		if created {
+			status = models.SensorBulkCreated
+		}
+		results[i] = models.SensorBulkResult{Index: i, ID: sensor.ID, Status: status, Sensor: sensor}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// sqliteUpsertOne inserts or updates a single sensor within tx, reporting
+// whether the row was newly created.
+func sqliteUpsertOne(tx *sql.Tx, item *models.SensorUpsertItem) (*models.Sensor, bool, error) {
+	if err := item.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	var existed int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM sensors WHERE id = ?", item.ID).Scan(&existed); err != nil {
+		return nil, false, err
+	}
+
+	now := models.Now()
+	_, err := tx.Exec(`
+		INSERT INTO sensors (id, name, type, location, value, unit, status, last_reading, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			type = excluded.type,
+			location = excluded.location,
+			value = excluded.value,
+			unit = excluded.unit,
+			status = excluded.status,
+			last_reading = excluded.last_reading,
+			updated_at = excluded.updated_at
+	`, item.ID, item.Name, item.Type, item.Location, item.Value, item.Unit, item.Status, now, now, now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var s models.Sensor
+	err = tx.QueryRow(`
+		SELECT id, name, type, location, value, unit, status, last_reading, created_at, updated_at
+		FROM sensors WHERE id = ?
+	`, item.ID).Scan(&s.ID, &s.Name, &s.Type, &s.Location, &s.Value, &s.Unit, &s.Status, &s.LastReading, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &s, existed == 0, nil
+}
@@ -0,0 +1,255 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"iot-sensor-service/models"
+)
+
+// TriggerRepository defines the interface for persisting triggers and their
+// dead-lettered deliveries.
+type TriggerRepository interface {
+	// Create registers a new trigger for sensorID.
+	Create(sensorID string, req *models.TriggerCreate) (*models.Trigger, error)
+
+	// GetByID retrieves a trigger by its ID. Returns nil if none exists.
+	GetByID(id string) (*models.Trigger, error)
+
+	// ListBySensor returns every trigger registered for sensorID.
+	ListBySensor(sensorID string) ([]models.Trigger, error)
+
+	// ListAll returns every trigger, across all sensors.
+	ListAll() ([]models.Trigger, error)
+
+	// ListActiveForSensor returns the active triggers registered for
+	// sensorID, for evaluation against a new sensor reading.
+	ListActiveForSensor(sensorID string) ([]models.Trigger, error)
+
+	// CreateDeadLetter records a delivery that exhausted its retry budget.
+	CreateDeadLetter(dl *models.DeadLetter) (*models.DeadLetter, error)
+
+	// ListDeadLettersForTrigger returns the dead-lettered deliveries
+	// recorded for triggerID, most recent first.
+	ListDeadLettersForTrigger(triggerID string) ([]models.DeadLetter, error)
+
+	// DeleteDeadLetter removes a dead-lettered delivery, e.g. once it has
+	// been replayed.
+	DeleteDeadLetter(id string) error
+}
+
+// SQLiteTriggerRepository implements TriggerRepository using SQLite.
+type SQLiteTriggerRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTriggerRepository creates a new SQLite-backed trigger repository.
+func NewSQLiteTriggerRepository(db *sql.DB) *SQLiteTriggerRepository {
+	return &SQLiteTriggerRepository{db: db}
+}
+
+// Create registers a new trigger for sensorID.
+func (r *SQLiteTriggerRepository) Create(sensorID string, req *models.TriggerCreate) (*models.Trigger, error) {
+	id := uuid.New().String()
+	now := models.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO triggers (id, sensor_id, condition, webhook_url, secret, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 1, ?, ?)
+	`, id, sensorID, req.Condition, req.WebhookURL, req.Secret, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+// GetByID retrieves a trigger by its ID.
+func (r *SQLiteTriggerRepository) GetByID(id string) (*models.Trigger, error) {
+	t, err := scanTrigger(r.db.QueryRow(`
+		SELECT id, sensor_id, condition, webhook_url, secret, active, created_at, updated_at
+		FROM triggers WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ListBySensor returns every trigger registered for sensorID.
+func (r *SQLiteTriggerRepository) ListBySensor(sensorID string) ([]models.Trigger, error) {
+	return r.list(`
+		SELECT id, sensor_id, condition, webhook_url, secret, active, created_at, updated_at
+		FROM triggers WHERE sensor_id = ? ORDER BY created_at
+	`, sensorID)
+}
+
+// ListAll returns every trigger, across all sensors.
+func (r *SQLiteTriggerRepository) ListAll() ([]models.Trigger, error) {
+	return r.list(`
+		SELECT id, sensor_id, condition, webhook_url, secret, active, created_at, updated_at
+		FROM triggers ORDER BY created_at
+	`)
+}
+
+// ListActiveForSensor returns the active triggers registered for sensorID.
+func (r *SQLiteTriggerRepository) ListActiveForSensor(sensorID string) ([]models.Trigger, error) {
+	return r.list(`
+		SELECT id, sensor_id, condition, webhook_url, secret, active, created_at, updated_at
+		FROM triggers WHERE sensor_id = ? AND active = 1 ORDER BY created_at
+	`, sensorID)
+}
+
+func (r *SQLiteTriggerRepository) list(query string, args ...interface{}) ([]models.Trigger, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var triggers []models.Trigger
+	for rows.Next() {
+		t, err := scanTrigger(rows)
+		if err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, *t)
+	}
+
+	if triggers == nil {
+		triggers = []models.Trigger{}
+	}
+
+	return triggers, rows.Err()
+}
+
+// triggerScanner is satisfied by both *sql.Row and *sql.Rows.
+type triggerScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTrigger(row triggerScanner) (*models.Trigger, error) {
+	var t models.Trigger
+	if err := row.Scan(&t.ID, &t.SensorID, &t.Condition, &t.WebhookURL, &t.Secret, &t.Active, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateDeadLetter records a delivery that exhausted its retry budget.
+func (r *SQLiteTriggerRepository) CreateDeadLetter(dl *models.DeadLetter) (*models.DeadLetter, error) {
+	id := uuid.New().String()
+	now := models.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO trigger_dead_letters (id, trigger_id, correlation_id, payload, attempts, last_error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, id, dl.TriggerID, dl.CorrelationID, dl.Payload, dl.Attempts, dl.LastError, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DeadLetter{
+		ID:            id,
+		TriggerID:     dl.TriggerID,
+		CorrelationID: dl.CorrelationID,
+		Payload:       dl.Payload,
+		Attempts:      dl.Attempts,
+		LastError:     dl.LastError,
+		CreatedAt:     now,
+	}, nil
+}
+
+// ListDeadLettersForTrigger returns the dead-lettered deliveries recorded
+// for triggerID, most recent first.
+func (r *SQLiteTriggerRepository) ListDeadLettersForTrigger(triggerID string) ([]models.DeadLetter, error) {
+	rows, err := r.db.Query(`
+		SELECT id, trigger_id, correlation_id, payload, attempts, last_error, created_at
+		FROM trigger_dead_letters WHERE trigger_id = ? ORDER BY created_at DESC
+	`, triggerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []models.DeadLetter
+	for rows.Next() {
+		var dl models.DeadLetter
+		if err := rows.Scan(&dl.ID, &dl.TriggerID, &dl.CorrelationID, &dl.Payload, &dl.Attempts, &dl.LastError, &dl.CreatedAt); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, dl)
+	}
+
+	if deadLetters == nil {
+		deadLetters = []models.DeadLetter{}
+	}
+
+	return deadLetters, rows.Err()
+}
+
+// DeleteDeadLetter removes a dead-lettered delivery, e.g. once replayed.
+func (r *SQLiteTriggerRepository) DeleteDeadLetter(id string) error {
+	result, err := r.db.Exec("DELETE FROM trigger_dead_letters WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// errTriggersUnsupported is returned by every UnsupportedTriggerRepository method.
+var errTriggersUnsupported = errors.New("triggers are only supported with the sqlite backend")
+
+// UnsupportedTriggerRepository is a stand-in TriggerRepository for backends
+// that don't have a trigger persistence implementation yet; every method
+// fails with errTriggersUnsupported instead of the caller hitting a nil pointer.
+type UnsupportedTriggerRepository struct{}
+
+// NewUnsupportedTriggerRepository creates an UnsupportedTriggerRepository.
+func NewUnsupportedTriggerRepository() *UnsupportedTriggerRepository {
+	return &UnsupportedTriggerRepository{}
+}
+
+func (r *UnsupportedTriggerRepository) Create(sensorID string, req *models.TriggerCreate) (*models.Trigger, error) {
+	return nil, errTriggersUnsupported
+}
+
+func (r *UnsupportedTriggerRepository) GetByID(id string) (*models.Trigger, error) {
+	return nil, errTriggersUnsupported
+}
+
+func (r *UnsupportedTriggerRepository) ListBySensor(sensorID string) ([]models.Trigger, error) {
+	return nil, errTriggersUnsupported
+}
+
+func (r *UnsupportedTriggerRepository) ListAll() ([]models.Trigger, error) {
+	return nil, errTriggersUnsupported
+}
+
+func (r *UnsupportedTriggerRepository) ListActiveForSensor(sensorID string) ([]models.Trigger, error) {
+	return nil, errTriggersUnsupported
+}
+
+func (r *UnsupportedTriggerRepository) CreateDeadLetter(dl *models.DeadLetter) (*models.DeadLetter, error) {
+	return nil, errTriggersUnsupported
+}
+
+func (r *UnsupportedTriggerRepository) ListDeadLettersForTrigger(triggerID string) ([]models.DeadLetter, error) {
+	return nil, errTriggersUnsupported
+}
+
+func (r *UnsupportedTriggerRepository) DeleteDeadLetter(id string) error {
+	return errTriggersUnsupported
+}
@@ -0,0 +1,78 @@
+// Package retention periodically prunes raw sensor readings older than a
+// configurable per-sensor-type window.
+package retention
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"iot-sensor-service/models"
+	"iot-sensor-service/repositories"
+)
+
+// Worker periodically deletes raw readings older than the configured
+// retention window for each known sensor type.
+type Worker struct {
+	repo        repositories.ReadingRepository
+	interval    time.Duration
+	defaultDays int
+	daysByType  map[string]int
+}
+
+// NewWorker creates a Worker that sweeps repo every interval, keeping
+// defaultDays of raw readings per sensor type unless overridden in
+// daysByType.
+func NewWorker(repo repositories.ReadingRepository, interval time.Duration, defaultDays int, daysByType map[string]int) *Worker {
+	return &Worker{
+		repo:        repo,
+		interval:    interval,
+		defaultDays: defaultDays,
+		daysByType:  daysByType,
+	}
+}
+
+// Run sweeps every sensor type on a ticker until ctx is canceled. Intended
+// to be run in its own goroutine.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.sweep()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.sweep()
+		}
+	}
+}
+
+func (w *Worker) sweep() {
+	types := make([]string, 0, len(models.ValidSensorTypes))
+	for t := range models.ValidSensorTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, sensorType := range types {
+		days := w.defaultDays
+		if override, ok := w.daysByType[sensorType]; ok {
+			days = override
+		}
+
+		cutoff := time.Now().UTC().Add(-time.Duration(days) * 24 * time.Hour).Format(time.RFC3339)
+
+		deleted, err := w.repo.DeleteOlderThan(sensorType, cutoff)
+		if err != nil {
+			slog.Error("Failed to apply retention policy", "sensor_type", sensorType, "error", err)
+			continue
+		}
+		if deleted > 0 {
+			slog.Info("Applied retention policy", "sensor_type", sensorType, "deleted", deleted, "cutoff", cutoff)
+		}
+	}
+}
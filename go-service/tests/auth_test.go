@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/auth"
+	"iot-sensor-service/database"
+	"iot-sensor-service/handlers"
+	"iot-sensor-service/middleware"
+)
+
+const testWatcherMachineID = "watcher-01"
+const testWatcherPassword = "test-watcher-password"
+
+// setupWatcherTestRouter wires up a router with JWT-only auth (mode "both",
+// so the static token still works for comparison) and a seeded watcher
+// credential, returning jwtCfg so tests can mint tokens with custom expiry.
+func setupWatcherTestRouter(t *testing.T, jwtCfg *auth.JWTConfig) (*gin.Engine, func()) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpFile.Close()
+	dbPath := tmpFile.Name()
+
+	db, err := database.Connect(dbPath)
+	if err != nil {
+		os.Remove(dbPath)
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.InitSchema(db); err != nil {
+		db.Close()
+		os.Remove(dbPath)
+		t.Fatalf("Failed to initialize schema: %v", err)
+	}
+
+	tokenStore := auth.NewSQLiteTokenStore(db)
+	watcherStore := auth.NewSQLiteWatcherStore(db)
+
+	hashed, err := auth.HashSecret(testWatcherPassword)
+	if err != nil {
+		t.Fatalf("Failed to hash watcher password: %v", err)
+	}
+	if err := watcherStore.Upsert(testWatcherMachineID, hashed); err != nil {
+		t.Fatalf("Failed to seed watcher: %v", err)
+	}
+
+	watcherHandler := handlers.NewWatcherHandler(watcherStore, jwtCfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/watchers/login", watcherHandler.Login)
+	router.POST("/v1/watchers/refresh", watcherHandler.Refresh)
+
+	protected := router.Group("/")
+	protected.Use(middleware.AuthMiddleware(auth.NewJWTAuth(jwtCfg), auth.NewStaticTokenAuth(tokenStore, testToken)))
+	protected.GET("/whoami", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"machine_id": middleware.GetMachineID(c)})
+	})
+
+	cleanup := func() {
+		db.Close()
+		os.Remove(dbPath)
+	}
+
+	return router, cleanup
+}
+
+func login(t *testing.T, router *gin.Engine, machineID, password string) (int, loginResponse) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"machine_id": machineID, "password": password})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/watchers/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var resp loginResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+// loginResponse mirrors the envelope returned by /v1/watchers/login and
+// /v1/watchers/refresh.
+type loginResponse struct {
+	Code   int    `json:"code"`
+	Expire string `json:"expire"`
+	Token  string `json:"token"`
+}
+
+func TestWatcherLoginIssuesUsableToken(t *testing.T) {
+	jwtCfg := &auth.JWTConfig{Secret: testJWTSecret, Expiry: time.Hour, RefreshWindow: 5 * time.Minute}
+	router, cleanup := setupWatcherTestRouter(t, jwtCfg)
+	defer cleanup()
+
+	code, resp := login(t, router, testWatcherMachineID, testWatcherPassword)
+	if code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if resp.Token == "" {
+		t.Fatal("Expected a non-empty token")
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+resp.Token)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var body map[string]string
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body["machine_id"] != testWatcherMachineID {
+		t.Errorf("Expected machine_id %q, got %q", testWatcherMachineID, body["machine_id"])
+	}
+}
+
+func TestWatcherLoginInvalidPassword(t *testing.T) {
+	jwtCfg := &auth.JWTConfig{Secret: testJWTSecret, Expiry: time.Hour, RefreshWindow: 5 * time.Minute}
+	router, cleanup := setupWatcherTestRouter(t, jwtCfg)
+	defer cleanup()
+
+	code, _ := login(t, router, testWatcherMachineID, "wrong-password")
+	if code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", code)
+	}
+}
+
+func TestProtectedRequestWithExpiredJWTRejected(t *testing.T) {
+	jwtCfg := &auth.JWTConfig{Secret: testJWTSecret, Expiry: -time.Minute, RefreshWindow: 5 * time.Minute}
+	router, cleanup := setupWatcherTestRouter(t, jwtCfg)
+	defer cleanup()
+
+	_, resp := login(t, router, testWatcherMachineID, testWatcherPassword)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+resp.Token)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an expired token, got %d", w.Code)
+	}
+}
+
+func TestProtectedRequestWithTamperedJWTRejected(t *testing.T) {
+	jwtCfg := &auth.JWTConfig{Secret: testJWTSecret, Expiry: time.Hour, RefreshWindow: 5 * time.Minute}
+	router, cleanup := setupWatcherTestRouter(t, jwtCfg)
+	defer cleanup()
+
+	_, resp := login(t, router, testWatcherMachineID, testWatcherPassword)
+	tampered := resp.Token[:len(resp.Token)-1] + "x"
+	if tampered == resp.Token {
+		tampered = resp.Token[:len(resp.Token)-1] + "y"
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+tampered)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a tampered signature, got %d", w.Code)
+	}
+}
+
+func TestWatcherRefreshOutsideWindowRejected(t *testing.T) {
+	jwtCfg := &auth.JWTConfig{Secret: testJWTSecret, Expiry: time.Hour, RefreshWindow: 5 * time.Minute}
+	router, cleanup := setupWatcherTestRouter(t, jwtCfg)
+	defer cleanup()
+
+	_, resp := login(t, router, testWatcherMachineID, testWatcherPassword)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/watchers/refresh", nil)
+	req.Header.Set("Authorization", "Bearer "+resp.Token)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 when refreshing a token far from expiry, got %d", w.Code)
+	}
+}
+
+func TestWatcherRefreshWithinWindowIssuesNewToken(t *testing.T) {
+	jwtCfg := &auth.JWTConfig{Secret: testJWTSecret, Expiry: time.Minute, RefreshWindow: 5 * time.Minute}
+	router, cleanup := setupWatcherTestRouter(t, jwtCfg)
+	defer cleanup()
+
+	_, first := login(t, router, testWatcherMachineID, testWatcherPassword)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/watchers/refresh", nil)
+	req.Header.Set("Authorization", "Bearer "+first.Token)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var refreshed loginResponse
+	json.Unmarshal(w.Body.Bytes(), &refreshed)
+	if refreshed.Token == "" {
+		t.Error("Expected a non-empty refreshed token")
+	}
+}
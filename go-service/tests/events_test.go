@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventsStreamReceivesSensorCreated(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	streamReq, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/events?types=sensor.created", nil)
+	streamReq.Header.Set("Authorization", "Bearer "+testToken)
+
+	streamResp, err := http.DefaultClient.Do(streamReq)
+	if err != nil {
+		t.Fatalf("Failed to connect to event stream: %v", err)
+	}
+	defer streamResp.Body.Close()
+
+	if streamResp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", streamResp.StatusCode)
+	}
+
+	// Give the subscriber a moment to register before publishing.
+	time.Sleep(50 * time.Millisecond)
+
+	newSensor := map[string]interface{}{
+		"name":     "Event Sensor",
+		"type":     "temperature",
+		"location": "lab",
+		"value":    1.0,
+		"unit":     "celsius",
+		"status":   "active",
+	}
+	body, _ := json.Marshal(newSensor)
+
+	createReq, _ := http.NewRequest("POST", server.URL+"/sensors", bytes.NewBuffer(body))
+	createReq.Header.Set("Authorization", "Bearer "+testToken)
+	createReq.Header.Set("Content-Type", "application/json")
+
+	createResp, err := http.DefaultClient.Do(createReq)
+	if err != nil {
+		t.Fatalf("Failed to create sensor: %v", err)
+	}
+	defer createResp.Body.Close()
+
+	reader := bufio.NewReader(streamResp.Body)
+	found := false
+	for i := 0; i < 20; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		if strings.Contains(line, "sensor.created") {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Error("Expected to observe a sensor.created SSE event")
+	}
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"iot-sensor-service/auth"
+)
+
+// generateTestCA creates a minimal self-signed CA certificate and key, for
+// use as the root of trust in mTLS tests.
+func generateTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse CA certificate: %v", err)
+	}
+
+	return cert, key
+}
+
+// generateTestClientCert issues a client certificate for commonName, signed
+// by ca/caKey, with the given serial number.
+func generateTestClientCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, commonName string, serial int64) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate client key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Failed to create client certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse client certificate: %v", err)
+	}
+
+	return cert
+}
+
+// ginContextWithPeerCert builds a *gin.Context whose request carries cert as
+// its sole TLS peer certificate, as gin would see behind a terminated mTLS
+// handshake.
+func ginContextWithPeerCert(cert *x509.Certificate) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	req := httptest.NewRequest(http.MethodGet, "/sensors", nil)
+	if cert != nil {
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+	c.Request = req
+	return c
+}
+
+func TestMTLSAuthAcceptsCertSignedByTrustedCA(t *testing.T) {
+	ca, caKey := generateTestCA(t)
+	clientCert := generateTestClientCert(t, ca, caKey, "sensor-gateway-01", 2)
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca)
+
+	authn := auth.NewMTLSAuth(caPool, "", nil)
+	identity, err := authn.Authenticate(ginContextWithPeerCert(clientCert))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if identity.MachineID != "sensor-gateway-01" {
+		t.Errorf("Expected machine_id 'sensor-gateway-01', got %q", identity.MachineID)
+	}
+}
+
+func TestMTLSAuthRejectsCertFromUntrustedCA(t *testing.T) {
+	trustedCA, _ := generateTestCA(t)
+	otherCA, otherCAKey := generateTestCA(t)
+	clientCert := generateTestClientCert(t, otherCA, otherCAKey, "sensor-gateway-01", 2)
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(trustedCA)
+
+	authn := auth.NewMTLSAuth(caPool, "", nil)
+	if _, err := authn.Authenticate(ginContextWithPeerCert(clientCert)); err == nil {
+		t.Error("Expected an error for a certificate from an untrusted CA")
+	}
+}
+
+func TestMTLSAuthNoCertIsNoCredential(t *testing.T) {
+	ca, _ := generateTestCA(t)
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca)
+
+	authn := auth.NewMTLSAuth(caPool, "", nil)
+	if _, err := authn.Authenticate(ginContextWithPeerCert(nil)); err != auth.ErrNoCredential {
+		t.Errorf("Expected ErrNoCredential when no peer certificate is presented, got %v", err)
+	}
+}
+
+func TestMTLSAuthRejectsRevokedCert(t *testing.T) {
+	ca, caKey := generateTestCA(t)
+	clientCert := generateTestClientCert(t, ca, caKey, "sensor-gateway-01", 42)
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca)
+
+	crlTemplate := &x509.RevocationList{
+		Number: big.NewInt(1),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: clientCert.SerialNumber, RevocationTime: time.Now()},
+		},
+		ThisUpdate: time.Now(),
+		NextUpdate: time.Now().Add(time.Hour),
+	}
+	crlDER, err := x509.CreateRevocationList(rand.Reader, crlTemplate, ca, caKey)
+	if err != nil {
+		t.Fatalf("Failed to create CRL: %v", err)
+	}
+
+	crlFile, err := os.CreateTemp("", "test-*.crl")
+	if err != nil {
+		t.Fatalf("Failed to create temp CRL file: %v", err)
+	}
+	defer os.Remove(crlFile.Name())
+	if _, err := crlFile.Write(crlDER); err != nil {
+		t.Fatalf("Failed to write CRL: %v", err)
+	}
+	crlFile.Close()
+
+	crl := auth.NewCRLChecker(crlFile.Name(), time.Hour)
+	// Run performs an initial synchronous poll before entering its ticker
+	// loop, so a context that is already near-expired is enough to populate
+	// the revoked set and return without racing a background goroutine.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	crl.Run(ctx)
+
+	if !crl.IsRevoked(clientCert.SerialNumber) {
+		t.Fatal("Expected the client certificate's serial to be revoked")
+	}
+
+	authn := auth.NewMTLSAuth(caPool, "", crl)
+	if _, err := authn.Authenticate(ginContextWithPeerCert(clientCert)); err == nil {
+		t.Error("Expected an error for a revoked certificate")
+	}
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"iot-sensor-service/database"
+	"iot-sensor-service/models"
+	"iot-sensor-service/repositories"
+)
+
+// conformanceBackend pairs a SensorRepository with the teardown for its
+// backing database, so the same test body can run against every driver.
+type conformanceBackend struct {
+	name  string
+	repo  repositories.SensorRepository
+	close func()
+}
+
+// conformanceBackends spins up one SensorRepository per supported driver: an
+// on-disk SQLite file, and (when Docker is available) a real Postgres
+// container via testcontainers-go. Running the same assertions against both
+// is what catches a repository whose SQLite and Postgres implementations
+// have quietly drifted apart.
+func conformanceBackends(t *testing.T) []conformanceBackend {
+	t.Helper()
+
+	backends := []conformanceBackend{sqliteConformanceBackend(t)}
+	if pg, ok := postgresConformanceBackend(t); ok {
+		backends = append(backends, pg)
+	}
+	return backends
+}
+
+func sqliteConformanceBackend(t *testing.T) conformanceBackend {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "conformance-*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	db, err := database.Connect(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to connect to SQLite: %v", err)
+	}
+	if err := database.InitSchema(db); err != nil {
+		t.Fatalf("Failed to init SQLite schema: %v", err)
+	}
+
+	return conformanceBackend{
+		name: "sqlite",
+		repo: repositories.NewSQLiteSensorRepository(db),
+		close: func() {
+			db.Close()
+			os.Remove(tmpFile.Name())
+		},
+	}
+}
+
+// postgresConformanceBackend starts an ephemeral Postgres container and
+// returns a PostgresSensorRepository backed by it. It skips (rather than
+// fails) the caller when Docker isn't reachable, since that's a test
+// environment gap, not a repository bug.
+func postgresConformanceBackend(t *testing.T) (conformanceBackend, bool) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	container, err := tcpostgres.Run(ctx, "postgres:16-alpine",
+		tcpostgres.WithDatabase("sensors"),
+		tcpostgres.WithUsername("sensors"),
+		tcpostgres.WithPassword("sensors"),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("5432/tcp").WithStartupTimeout(30*time.Second)),
+	)
+	if err != nil {
+		t.Logf("Docker unavailable, skipping Postgres conformance backend: %v", err)
+		return conformanceBackend{}, false
+	}
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("Failed to get Postgres connection string: %v", err)
+	}
+
+	driver, err := database.NewDriver(dsn)
+	if err != nil {
+		t.Fatalf("Failed to build Postgres driver: %v", err)
+	}
+
+	db, err := driver.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Failed to connect to Postgres: %v", err)
+	}
+	if err := driver.InitSchema(ctx, db); err != nil {
+		t.Fatalf("Failed to init Postgres schema: %v", err)
+	}
+
+	return conformanceBackend{
+		name: "postgres",
+		repo: repositories.NewPostgresSensorRepository(db),
+		close: func() {
+			db.Close()
+			_ = container.Terminate(ctx)
+		},
+	}, true
+}
+
+// TestSensorRepositoryConformance runs the same SensorRepository assertions
+// against every driver, so the contract (ID assignment, zero-value
+// semantics, upsert create-vs-update detection) is verified identically
+// rather than only ever exercised against SQLite.
+func TestSensorRepositoryConformance(t *testing.T) {
+	for _, backend := range conformanceBackends(t) {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			defer backend.close()
+			testSensorRepositoryCRUD(t, backend.repo)
+			testSensorRepositoryUpsertMany(t, backend.repo)
+		})
+	}
+}
+
+func testSensorRepositoryCRUD(t *testing.T, repo repositories.SensorRepository) {
+	t.Helper()
+
+	created, err := repo.Create(&models.SensorCreate{
+		Name:     "Conformance Sensor",
+		Type:     "temperature",
+		Location: "lab",
+		Value:    21.5,
+		Unit:     "celsius",
+		Status:   "active",
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("Expected Create to assign an ID")
+	}
+
+	fetched, err := repo.GetByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if fetched == nil {
+		t.Fatal("Expected GetByID to find the created sensor")
+	}
+	if fetched.Value != 21.5 {
+		t.Errorf("Expected value 21.5, got %v", fetched.Value)
+	}
+
+	inactive := "inactive"
+	updated, err := repo.Update(created.ID, &models.SensorUpdate{Status: &inactive})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.Status != "inactive" {
+		t.Errorf("Expected status 'inactive', got %q", updated.Status)
+	}
+
+	all, err := repo.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	found := false
+	for _, s := range all {
+		if s.ID == created.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected GetAll to include the created sensor")
+	}
+
+	if err := repo.Delete(created.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if gone, err := repo.GetByID(created.ID); err != nil {
+		t.Fatalf("GetByID after delete failed: %v", err)
+	} else if gone != nil {
+		t.Error("Expected sensor to be gone after Delete")
+	}
+}
+
+func testSensorRepositoryUpsertMany(t *testing.T, repo repositories.SensorRepository) {
+	t.Helper()
+
+	items := []models.SensorUpsertItem{
+		{ID: "conformance-bulk-1", Name: "Bulk A", Type: "humidity", Location: "lab", Value: 40, Unit: "percent", Status: "active"},
+		{ID: "conformance-bulk-2", Name: "Bulk B", Type: "humidity", Location: "lab", Value: 45, Unit: "percent", Status: "active"},
+	}
+
+	results, err := repo.UpsertMany(items)
+	if err != nil {
+		t.Fatalf("UpsertMany failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 upsert results, got %d", len(results))
+	}
+	for _, res := range results {
+		if res.Status != models.SensorBulkCreated {
+			t.Errorf("Expected both sensors to be newly created, got %+v", res)
+		}
+	}
+
+	items[0].Value = 50
+	results, err = repo.UpsertMany(items)
+	if err != nil {
+		t.Fatalf("UpsertMany (second pass) failed: %v", err)
+	}
+	for _, res := range results {
+		if res.ID == "conformance-bulk-1" && res.Status != models.SensorBulkUpdated {
+			t.Errorf("Expected conformance-bulk-1 to be an update on the second pass, got %+v", res)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"iot-sensor-service/database"
+	"iot-sensor-service/models"
+	"iot-sensor-service/repositories"
+	"iot-sensor-service/retention"
+)
+
+// setupReadingRepoWithSensor creates a temporary SQLite database with a
+// single sensor of the given type, returning its reading repository, the
+// sensor's id, and a cleanup function.
+func setupReadingRepoWithSensor(t *testing.T, sensorType string) (*repositories.SQLiteReadingRepository, string, func()) {
+	tmpFile, err := os.CreateTemp("", "test-retention-*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpFile.Close()
+	dbPath := tmpFile.Name()
+
+	db, err := database.Connect(dbPath)
+	if err != nil {
+		os.Remove(dbPath)
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.InitSchema(db); err != nil {
+		db.Close()
+		os.Remove(dbPath)
+		t.Fatalf("Failed to initialize schema: %v", err)
+	}
+
+	sensorRepo := repositories.NewSQLiteSensorRepository(db)
+	sensor, err := sensorRepo.Create(&models.SensorCreate{
+		Name:     "Retention Sensor",
+		Type:     sensorType,
+		Location: "basement",
+		Value:    1.0,
+		Unit:     "ppm",
+		Status:   "active",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sensor: %v", err)
+	}
+
+	readingRepo := repositories.NewSQLiteReadingRepository(db)
+
+	cleanup := func() {
+		db.Close()
+		os.Remove(dbPath)
+	}
+
+	return readingRepo, sensor.ID, cleanup
+}
+
+func TestDeleteOlderThanRemovesOnlyReadingsBeforeCutoff(t *testing.T) {
+	readingRepo, sensorID, cleanup := setupReadingRepoWithSensor(t, "co2")
+	defer cleanup()
+
+	_, err := readingRepo.CreateMany(sensorID, []models.ReadingCreate{
+		{Value: 1.0, Unit: "ppm", TakenAt: "2024-01-01T00:00:00Z"},
+		{Value: 2.0, Unit: "ppm", TakenAt: "2024-01-05T00:00:00Z"},
+		{Value: 3.0, Unit: "ppm", TakenAt: "2024-01-10T00:00:00Z"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed readings: %v", err)
+	}
+
+	// Sensor creation itself records one reading at the current time, on top
+	// of the three seeded above.
+	before, err := readingRepo.ListByRange(sensorID, "", "", 0, "asc")
+	if err != nil {
+		t.Fatalf("ListByRange failed: %v", err)
+	}
+	if len(before) != 4 {
+		t.Fatalf("Expected 4 readings before sweeping, got %d", len(before))
+	}
+
+	// A cutoff exactly on the middle reading's timestamp should leave it and
+	// the later readings in place, deleting only the strictly-older one.
+	deleted, err := readingRepo.DeleteOlderThan("co2", "2024-01-05T00:00:00Z")
+	if err != nil {
+		t.Fatalf("DeleteOlderThan failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 row deleted, got %d", deleted)
+	}
+
+	remaining, err := readingRepo.ListByRange(sensorID, "", "", 0, "asc")
+	if err != nil {
+		t.Fatalf("ListByRange failed: %v", err)
+	}
+	if len(remaining) != 3 {
+		t.Fatalf("Expected 3 remaining readings, got %d", len(remaining))
+	}
+}
+
+func TestDeleteOlderThanIgnoresOtherSensorTypes(t *testing.T) {
+	readingRepo, sensorID, cleanup := setupReadingRepoWithSensor(t, "motion")
+	defer cleanup()
+
+	_, err := readingRepo.CreateMany(sensorID, []models.ReadingCreate{
+		{Value: 1.0, Unit: "bool", TakenAt: "2024-01-01T00:00:00Z"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed readings: %v", err)
+	}
+
+	// Sweeping a different sensor type with a far-future cutoff must not
+	// touch this sensor's readings.
+	deleted, err := readingRepo.DeleteOlderThan("co2", "2099-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("DeleteOlderThan failed: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("Expected 0 rows deleted for unrelated sensor type, got %d", deleted)
+	}
+
+	// Sensor creation itself records one reading on top of the seeded one.
+	remaining, err := readingRepo.ListByRange(sensorID, "", "", 0, "asc")
+	if err != nil {
+		t.Fatalf("ListByRange failed: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("Expected the motion sensor's readings to survive, got %d remaining", len(remaining))
+	}
+}
+
+func TestRetentionWorkerSweepsOnStart(t *testing.T) {
+	readingRepo, sensorID, cleanup := setupReadingRepoWithSensor(t, "co2")
+	defer cleanup()
+
+	_, err := readingRepo.CreateMany(sensorID, []models.ReadingCreate{
+		{Value: 1.0, Unit: "ppm", TakenAt: "2000-01-01T00:00:00Z"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed readings: %v", err)
+	}
+
+	worker := retention.NewWorker(readingRepo, time.Hour, 7, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go worker.Run(ctx)
+	defer cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		remaining, err := readingRepo.ListByRange(sensorID, "", "", 0, "asc")
+		if err != nil {
+			t.Fatalf("ListByRange failed: %v", err)
+		}
+
+		sweptOld := true
+		for _, reading := range remaining {
+			if reading.TakenAt == "2000-01-01T00:00:00Z" {
+				sweptOld = false
+			}
+		}
+		if sweptOld {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the year-2000 reading to be swept within the deadline, %d remaining", len(remaining))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
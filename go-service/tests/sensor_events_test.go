@@ -0,0 +1,279 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readSSEEvents reads up to max SSE events (each "id:"/"event:"/"data:" block)
+// from r, returning their decoded ids and types in arrival order. It stops
+// early (without error) if the stream ends or no event arrives within the
+// per-read deadline.
+func readSSEEvents(t *testing.T, r *bufio.Reader, max int) []map[string]interface{} {
+	t.Helper()
+
+	var events []map[string]interface{}
+	var dataLine string
+
+	for len(events) < max {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case strings.HasPrefix(line, "data: "):
+			dataLine = strings.TrimPrefix(line, "data: ")
+		case line == "" && dataLine != "":
+			var event map[string]interface{}
+			if err := json.Unmarshal([]byte(dataLine), &event); err == nil {
+				events = append(events, event)
+			}
+			dataLine = ""
+		}
+	}
+
+	return events
+}
+
+func TestSensorEventsStreamScopedToOneSensor(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	sensorA := createTestSensorForReadings(t, router)
+	sensorB := createTestSensorForReadings(t, router)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	streamReq, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/sensors/"+sensorA+"/events", nil)
+	streamReq.Header.Set("Authorization", "Bearer "+testToken)
+
+	streamResp, err := http.DefaultClient.Do(streamReq)
+	if err != nil {
+		t.Fatalf("Failed to connect to event stream: %v", err)
+	}
+	defer streamResp.Body.Close()
+
+	if streamResp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", streamResp.StatusCode)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	// Updating sensor B should not appear on sensor A's stream.
+	updateB := map[string]interface{}{"value": 99.0}
+	bodyB, _ := json.Marshal(updateB)
+	reqB, _ := http.NewRequest("PUT", server.URL+"/sensors/"+sensorB, bytes.NewBuffer(bodyB))
+	reqB.Header.Set("Authorization", "Bearer "+testToken)
+	reqB.Header.Set("Content-Type", "application/json")
+	respB, err := http.DefaultClient.Do(reqB)
+	if err != nil {
+		t.Fatalf("Failed to update sensor B: %v", err)
+	}
+	respB.Body.Close()
+
+	// Updating sensor A should appear.
+	updateA := map[string]interface{}{"value": 55.0}
+	bodyA, _ := json.Marshal(updateA)
+	reqA, _ := http.NewRequest("PUT", server.URL+"/sensors/"+sensorA, bytes.NewBuffer(bodyA))
+	reqA.Header.Set("Authorization", "Bearer "+testToken)
+	reqA.Header.Set("Content-Type", "application/json")
+	respA, err := http.DefaultClient.Do(reqA)
+	if err != nil {
+		t.Fatalf("Failed to update sensor A: %v", err)
+	}
+	respA.Body.Close()
+
+	reader := bufio.NewReader(streamResp.Body)
+	events := readSSEEvents(t, reader, 1)
+
+	if len(events) != 1 {
+		t.Fatalf("Expected exactly 1 event on sensor A's stream, got %d", len(events))
+	}
+
+	data, ok := events[0]["data"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected event data object, got %v", events[0]["data"])
+	}
+	if data["id"] != sensorA {
+		t.Errorf("Expected the streamed event to be about sensor A (%s), got %v", sensorA, data["id"])
+	}
+}
+
+func TestSensorEventsIncludeCorrelationID(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	streamReq, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/sensors/events", nil)
+	streamReq.Header.Set("Authorization", "Bearer "+testToken)
+
+	streamResp, err := http.DefaultClient.Do(streamReq)
+	if err != nil {
+		t.Fatalf("Failed to connect to event stream: %v", err)
+	}
+	defer streamResp.Body.Close()
+
+	time.Sleep(50 * time.Millisecond)
+
+	newSensor := map[string]interface{}{
+		"name": "Correlated Sensor", "type": "temperature", "location": "roof",
+		"value": 1.0, "unit": "celsius", "status": "active",
+	}
+	body, _ := json.Marshal(newSensor)
+
+	createReq, _ := http.NewRequest("POST", server.URL+"/sensors", bytes.NewBuffer(body))
+	createReq.Header.Set("Authorization", "Bearer "+testToken)
+	createReq.Header.Set("Content-Type", "application/json")
+	createReq.Header.Set("X-Correlation-ID", "test-correlation-id-123")
+
+	createResp, err := http.DefaultClient.Do(createReq)
+	if err != nil {
+		t.Fatalf("Failed to create sensor: %v", err)
+	}
+	createResp.Body.Close()
+
+	reader := bufio.NewReader(streamResp.Body)
+	events := readSSEEvents(t, reader, 1)
+
+	if len(events) != 1 {
+		t.Fatalf("Expected exactly 1 event, got %d", len(events))
+	}
+	if events[0]["correlation_id"] != "test-correlation-id-123" {
+		t.Errorf("Expected correlation_id to propagate from the originating request, got %v", events[0]["correlation_id"])
+	}
+}
+
+func TestSensorEventsResumeFromLastEventID(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Connect before the sensor even exists so the initial replay (since=0)
+	// starts from a clean slate: the first event observed is the creation.
+	ctx1, cancel1 := context.WithTimeout(context.Background(), 2*time.Second)
+	req1, _ := http.NewRequestWithContext(ctx1, "GET", server.URL+"/sensors/events", nil)
+	req1.Header.Set("Authorization", "Bearer "+testToken)
+	resp1, err := http.DefaultClient.Do(req1)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	sensorID := createTestSensorForReadings(t, router)
+
+	updateBody, _ := json.Marshal(map[string]interface{}{"value": 10.0})
+	updateReq, _ := http.NewRequest("PUT", server.URL+"/sensors/"+sensorID, bytes.NewBuffer(updateBody))
+	updateReq.Header.Set("Authorization", "Bearer "+testToken)
+	updateReq.Header.Set("Content-Type", "application/json")
+	updateResp, err := http.DefaultClient.Do(updateReq)
+	if err != nil {
+		t.Fatalf("Failed to update sensor: %v", err)
+	}
+	updateResp.Body.Close()
+
+	// created + updated.
+	firstEvents := readSSEEvents(t, bufio.NewReader(resp1.Body), 2)
+	resp1.Body.Close()
+	cancel1()
+
+	if len(firstEvents) != 2 {
+		t.Fatalf("Expected 2 events on the first connection, got %d", len(firstEvents))
+	}
+	lastSeq := fmt.Sprintf("%.0f", firstEvents[1]["seq"].(float64))
+
+	// A second, later update, made while disconnected.
+	updateBody2, _ := json.Marshal(map[string]interface{}{"value": 20.0})
+	updateReq2, _ := http.NewRequest("PUT", server.URL+"/sensors/"+sensorID, bytes.NewBuffer(updateBody2))
+	updateReq2.Header.Set("Authorization", "Bearer "+testToken)
+	updateReq2.Header.Set("Content-Type", "application/json")
+	updateResp2, err := http.DefaultClient.Do(updateReq2)
+	if err != nil {
+		t.Fatalf("Failed to update sensor: %v", err)
+	}
+	updateResp2.Body.Close()
+
+	// Reconnecting with Last-Event-ID should replay only the missed update.
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel2()
+	req2, _ := http.NewRequestWithContext(ctx2, "GET", server.URL+"/sensors/"+sensorID+"/events", nil)
+	req2.Header.Set("Authorization", "Bearer "+testToken)
+	req2.Header.Set("Last-Event-ID", lastSeq)
+	resp2, err := http.DefaultClient.Do(req2)
+	if err != nil {
+		t.Fatalf("Failed to reconnect: %v", err)
+	}
+	defer resp2.Body.Close()
+
+	replayed := readSSEEvents(t, bufio.NewReader(resp2.Body), 1)
+	if len(replayed) != 1 {
+		t.Fatalf("Expected exactly 1 replayed event after resuming, got %d", len(replayed))
+	}
+
+	data, ok := replayed[0]["data"].(map[string]interface{})
+	if !ok || data["value"].(float64) != 20.0 {
+		t.Errorf("Expected the replayed event to be the missed update (value=20), got %v", replayed[0]["data"])
+	}
+}
+
+func TestSensorEventsDisconnectOnClientCancel(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/sensors/events", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			if _, err := resp.Body.Read(buf); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected the stream read to fail after client cancellation")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the server to close the stream promptly after client cancellation")
+	}
+
+	resp.Body.Close()
+}
@@ -6,17 +6,23 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"iot-sensor-service/auth"
 	"iot-sensor-service/database"
+	"iot-sensor-service/events"
 	"iot-sensor-service/handlers"
 	"iot-sensor-service/middleware"
 	"iot-sensor-service/repositories"
+	"iot-sensor-service/triggers"
 )
 
 const testToken = "test-secret-token"
+const testJWTSecret = "test-jwt-secret"
 
 // setupTestRouter creates a test router with a temporary database.
 func setupTestRouter(t *testing.T) (*gin.Engine, func()) {
@@ -44,21 +50,56 @@ func setupTestRouter(t *testing.T) (*gin.Engine, func()) {
 
 	// Create repository and handlers
 	sensorRepo := repositories.NewSQLiteSensorRepository(db)
+	readingRepo := repositories.NewSQLiteReadingRepository(db)
+	triggerRepo := repositories.NewSQLiteTriggerRepository(db)
+	tokenStore := auth.NewSQLiteTokenStore(db)
+	watcherStore := auth.NewSQLiteWatcherStore(db)
+	bus := events.NewBus()
+	dispatcher := triggers.NewDispatcher(triggerRepo, 5)
+	evaluator := triggers.NewEvaluator(triggerRepo, readingRepo, dispatcher, bus)
 	healthHandler := handlers.NewHealthHandler()
-	sensorHandler := handlers.NewSensorHandler(sensorRepo)
+	sensorHandler := handlers.NewSensorHandler(sensorRepo, bus, evaluator)
+	readingHandler := handlers.NewReadingHandler(readingRepo, sensorRepo, bus, evaluator)
+	eventHandler := handlers.NewEventHandler(bus)
+	metricsHandler := handlers.NewMetricsHandler("")
+	adminTokenHandler := handlers.NewAdminTokenHandler(tokenStore)
+	triggerHandler := handlers.NewTriggerHandler(triggerRepo, sensorRepo, dispatcher)
+	jwtCfg := &auth.JWTConfig{Secret: testJWTSecret, Expiry: time.Hour, RefreshWindow: 5 * time.Minute}
+	watcherHandler := handlers.NewWatcherHandler(watcherStore, jwtCfg)
 
 	// Set up router
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.Use(middleware.AuthMiddleware(testToken))
+	router.Use(middleware.AuthMiddleware(auth.NewStaticTokenAuth(tokenStore, testToken)))
 
 	// Register routes
 	router.GET("/health", healthHandler.Health)
-	router.GET("/sensors", sensorHandler.ListSensors)
-	router.GET("/sensors/:id", sensorHandler.GetSensor)
-	router.POST("/sensors", sensorHandler.CreateSensor)
-	router.PUT("/sensors/:id", sensorHandler.UpdateSensor)
-	router.DELETE("/sensors/:id", sensorHandler.DeleteSensor)
+	router.GET("/metrics", metricsHandler.Metrics)
+	router.POST("/v1/watchers/login", watcherHandler.Login)
+	router.POST("/v1/watchers/refresh", watcherHandler.Refresh)
+	router.GET("/sensors", middleware.RequireScope(auth.ScopeSensorsRead), sensorHandler.ListSensors)
+	router.GET("/sensors/:id", middleware.RequireScope(auth.ScopeSensorsRead), sensorHandler.GetSensor)
+	router.POST("/sensors", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.CreateSensor)
+	router.POST("/sensors:bulk", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.BulkUpsertSensors)
+	router.PUT("/sensors/:id", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.UpdateSensor)
+	router.DELETE("/sensors/:id", middleware.RequireScope(auth.ScopeSensorsWrite), sensorHandler.DeleteSensor)
+	router.POST("/sensors/:id/readings", middleware.RequireScope(auth.ScopeReadingsIngest), readingHandler.IngestReading)
+	router.POST("/readings:batch", middleware.RequireScope(auth.ScopeReadingsIngest), readingHandler.IngestBatch)
+	router.GET("/sensors/:id/readings", middleware.RequireScope(auth.ScopeSensorsRead), readingHandler.ListReadings)
+	router.GET("/sensors/:id/readings/aggregate", middleware.RequireScope(auth.ScopeSensorsRead), readingHandler.AggregateReadings)
+	router.GET("/events", middleware.RequireScope(auth.ScopeSensorsRead), eventHandler.Stream)
+	router.GET("/sensors/events", middleware.RequireScope(auth.ScopeSensorsRead), eventHandler.StreamSensorEvents)
+	router.GET("/sensors/:id/events", middleware.RequireScope(auth.ScopeSensorsRead), eventHandler.StreamSensorEventsByID)
+	router.POST("/sensors/:id/triggers", middleware.RequireScope(auth.ScopeTriggersWrite), triggerHandler.CreateTrigger)
+	router.GET("/sensors/:id/triggers", middleware.RequireScope(auth.ScopeTriggersRead), triggerHandler.ListTriggersForSensor)
+	router.GET("/triggers", middleware.RequireScope(auth.ScopeTriggersRead), triggerHandler.ListTriggers)
+	router.POST("/triggers/:id/replay", middleware.RequireScope(auth.ScopeTriggersWrite), triggerHandler.ReplayTrigger)
+
+	admin := router.Group("/admin")
+	admin.Use(middleware.RootAuthMiddleware(testToken))
+	admin.POST("/tokens", adminTokenHandler.CreateToken)
+	admin.GET("/tokens", adminTokenHandler.ListTokens)
+	admin.DELETE("/tokens/:id", adminTokenHandler.RevokeToken)
 
 	// Return cleanup function
 	cleanup := func() {
@@ -134,6 +175,24 @@ func TestHealthEndpoint(t *testing.T) {
 	}
 }
 
+func TestMetricsEndpoint(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", w.Code)
+	}
+
+	if !strings.Contains(w.Body.String(), "iot_http_requests_total") {
+		t.Errorf("Expected response to contain iot_http_requests_total metric")
+	}
+}
+
 func TestListSensorsEmpty(t *testing.T) {
 	router, cleanup := setupTestRouter(t)
 	defer cleanup()
@@ -422,3 +481,771 @@ func TestListSensorsAfterCreate(t *testing.T) {
 		t.Errorf("Expected 3 sensors, got %d", len(sensors))
 	}
 }
+
+func createTestSensorForReadings(t *testing.T, router *gin.Engine) string {
+	newSensor := map[string]interface{}{
+		"name":     "Readings Sensor",
+		"type":     "temperature",
+		"location": "attic",
+		"value":    70.0,
+		"unit":     "fahrenheit",
+		"status":   "active",
+	}
+	body, _ := json.Marshal(newSensor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	return created["id"].(string)
+}
+
+func TestIngestSingleReadingUpdatesSensor(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	reading := map[string]interface{}{
+		"value":    81.2,
+		"unit":     "fahrenheit",
+		"taken_at": "2024-01-01T12:00:00Z",
+	}
+	body, _ := json.Marshal(reading)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The parent sensor's value should reflect the new reading.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorID, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	var sensor map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &sensor)
+
+	if sensor["value"].(float64) != 81.2 {
+		t.Errorf("Expected sensor value 81.2, got %v", sensor["value"])
+	}
+}
+
+func TestIngestReadingAcceptsZeroValue(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	reading := map[string]interface{}{
+		"value":    0.0,
+		"unit":     "fahrenheit",
+		"taken_at": "2024-01-01T12:00:00Z",
+	}
+	body, _ := json.Marshal(reading)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for a zero reading value, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorID, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	var sensor map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &sensor)
+
+	if sensor["value"].(float64) != 0.0 {
+		t.Errorf("Expected sensor value 0, got %v", sensor["value"])
+	}
+}
+
+func TestIngestBatchReadings(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"value": 70.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:00:00Z"},
+			{"value": 72.0, "unit": "fahrenheit", "taken_at": "2024-01-01T11:00:00Z"},
+			{"value": 74.0, "unit": "fahrenheit", "taken_at": "2024-01-01T12:00:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	count := int(response["count"].(float64))
+	if count != 3 {
+		t.Errorf("Expected count 3, got %d", count)
+	}
+}
+
+func TestListReadingsWithRange(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"value": 70.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:00:00Z"},
+			{"value": 72.0, "unit": "fahrenheit", "taken_at": "2024-01-01T11:00:00Z"},
+			{"value": 74.0, "unit": "fahrenheit", "taken_at": "2024-01-01T12:00:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorID+"/readings?from=2024-01-01T10:30:00Z", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	count := int(response["count"].(float64))
+	if count != 2 {
+		t.Errorf("Expected count 2, got %d", count)
+	}
+}
+
+func TestListReadingsDownsampled(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"value": 70.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:00:00Z"},
+			{"value": 90.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:30:00Z"},
+			{"value": 80.0, "unit": "fahrenheit", "taken_at": "2024-01-01T11:00:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorID+"/readings?step=1h&agg=mean", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	buckets, ok := response["buckets"].([]interface{})
+	if !ok {
+		t.Fatalf("Expected a downsampled buckets response, got %v", response)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 one-hour buckets, got %d", len(buckets))
+	}
+
+	first := buckets[0].(map[string]interface{})
+	if first["value"].(float64) != 80.0 {
+		t.Errorf("Expected first bucket mean 80, got %v", first["value"])
+	}
+}
+
+func TestAggregateReadings(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"value": 70.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:05:00Z"},
+			{"value": 80.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:45:00Z"},
+			{"value": 90.0, "unit": "fahrenheit", "taken_at": "2024-01-01T11:15:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorID+"/readings/aggregate?bucket=1h&fn=avg", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	buckets := response["buckets"].([]interface{})
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(buckets))
+	}
+}
+
+func TestAggregateReadingsSum(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"value": 70.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:05:00Z"},
+			{"value": 80.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:45:00Z"},
+			{"value": 90.0, "unit": "fahrenheit", "taken_at": "2024-01-01T11:15:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorID+"/readings/aggregate?bucket=1h&fn=sum", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	buckets := response["buckets"].([]interface{})
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(buckets))
+	}
+
+	first := buckets[0].(map[string]interface{})
+	if first["value"].(float64) != 150.0 {
+		t.Errorf("Expected first bucket sum 150, got %v", first["value"])
+	}
+}
+
+func TestAggregateReadingsSubHourBuckets(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"value": 10.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:01:00Z"},
+			{"value": 20.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:03:00Z"},
+			{"value": 30.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:07:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorID+"/readings/aggregate?bucket=5m&fn=count", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	buckets := response["buckets"].([]interface{})
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 five-minute buckets, got %d", len(buckets))
+	}
+
+	first := buckets[0].(map[string]interface{})
+	if first["bucket"] != "2024-01-01T10:00:00Z" {
+		t.Errorf("Expected first bucket 2024-01-01T10:00:00Z, got %v", first["bucket"])
+	}
+	if first["value"].(float64) != 2.0 {
+		t.Errorf("Expected first bucket count 2, got %v", first["value"])
+	}
+
+	second := buckets[1].(map[string]interface{})
+	if second["bucket"] != "2024-01-01T10:05:00Z" {
+		t.Errorf("Expected second bucket 2024-01-01T10:05:00Z, got %v", second["bucket"])
+	}
+	if second["value"].(float64) != 1.0 {
+		t.Errorf("Expected second bucket count 1, got %v", second["value"])
+	}
+}
+
+func TestAggregateReadingsInvalidBucket(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/sensors/"+sensorID+"/readings/aggregate?bucket=7m&fn=avg", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for unsupported bucket width, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAggregateReadingsEmptyRange(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensorForReadings(t, router)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/sensors/"+sensorID+"/readings/aggregate?bucket=1h&fn=avg", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	buckets := response["buckets"].([]interface{})
+	if len(buckets) != 0 {
+		t.Fatalf("Expected 0 buckets for a sensor with no readings, got %d", len(buckets))
+	}
+}
+
+func TestIngestCrossSensorBatch(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorA := createTestSensorForReadings(t, router)
+	sensorB := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"sensor_id": sensorA, "value": 11.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:00:00Z"},
+			{"sensor_id": sensorB, "value": 22.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:01:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/readings:batch", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	count := int(response["count"].(float64))
+	if count != 2 {
+		t.Errorf("Expected count 2, got %d", count)
+	}
+
+	// Both sensors should reflect their own reading's value.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors/"+sensorB, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	var sensor map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &sensor)
+
+	if sensor["value"].(float64) != 22.0 {
+		t.Errorf("Expected sensor B value 22, got %v", sensor["value"])
+	}
+}
+
+func TestIngestCrossSensorBatchAcceptsZeroValue(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorA := createTestSensorForReadings(t, router)
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"sensor_id": sensorA, "value": 0.0, "unit": "fahrenheit", "taken_at": "2024-01-01T10:00:00Z"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/readings:batch", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for a batch item with a zero value, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	count := int(response["count"].(float64))
+	if count != 1 {
+		t.Errorf("Expected count 1, got %d", count)
+	}
+}
+
+func TestIngestCrossSensorBatchUnknownSensor(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	batch := map[string]interface{}{
+		"readings": []map[string]interface{}{
+			{"sensor_id": "nonexistent-id", "value": 1.0, "unit": "fahrenheit"},
+		},
+	}
+	body, _ := json.Marshal(batch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/readings:batch", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReadingsForNonexistentSensor(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	reading := map[string]interface{}{
+		"value": 1.0,
+		"unit":  "fahrenheit",
+	}
+	body, _ := json.Marshal(reading)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/nonexistent-id/readings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
+
+func TestBulkUpsertSensorsCreatesAndUpdates(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	// Seed one sensor directly via the regular create endpoint.
+	newSensor := map[string]interface{}{
+		"name":     "Existing Sensor",
+		"type":     "temperature",
+		"location": "garage",
+		"value":    60.0,
+		"unit":     "fahrenheit",
+		"status":   "active",
+	}
+	body, _ := json.Marshal(newSensor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	existingID := created["id"].(string)
+
+	bulk := map[string]interface{}{
+		"sensors": []map[string]interface{}{
+			{
+				"id":       existingID,
+				"name":     "Existing Sensor",
+				"type":     "temperature",
+				"location": "garage",
+				"value":    65.0,
+				"unit":     "fahrenheit",
+				"status":   "active",
+			},
+			{
+				"id":       "sensor-bulk-1",
+				"name":     "New Bulk Sensor",
+				"type":     "humidity",
+				"location": "shed",
+				"value":    40.0,
+				"unit":     "percent",
+				"status":   "active",
+			},
+			{
+				"id":       "sensor-bulk-bad",
+				"name":     "Invalid Bulk Sensor",
+				"type":     "not-a-real-type",
+				"location": "shed",
+				"value":    1.0,
+				"unit":     "percent",
+				"status":   "active",
+			},
+		},
+	}
+	body, _ = json.Marshal(bulk)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/sensors:bulk", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	results := response["results"].([]interface{})
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	first := results[0].(map[string]interface{})
+	if first["status"] != "updated" {
+		t.Errorf("Expected first item status 'updated', got '%v'", first["status"])
+	}
+
+	second := results[1].(map[string]interface{})
+	if second["status"] != "created" {
+		t.Errorf("Expected second item status 'created', got '%v'", second["status"])
+	}
+
+	third := results[2].(map[string]interface{})
+	if third["status"] != "error" {
+		t.Errorf("Expected third item status 'error', got '%v'", third["status"])
+	}
+	if third["error"] == nil {
+		t.Error("Expected an error detail for the invalid item")
+	}
+}
+
+func TestBulkUpsertSensorsAcceptsZeroValue(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	bulk := map[string]interface{}{
+		"sensors": []map[string]interface{}{
+			{
+				"id":       "sensor-bulk-zero",
+				"name":     "Zero Reading Sensor",
+				"type":     "humidity",
+				"location": "shed",
+				"value":    0.0,
+				"unit":     "percent",
+				"status":   "active",
+			},
+		},
+	}
+	body, _ := json.Marshal(bulk)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors:bulk", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	results := response["results"].([]interface{})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	result := results[0].(map[string]interface{})
+	if result["status"] != "created" {
+		t.Errorf("Expected status 'created' for a zero-value sensor, got '%v'", result["status"])
+	}
+}
+
+func TestAdminCreateTokenRequiresRootToken(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	tokenReq := map[string]interface{}{
+		"name":   "ci-runner",
+		"scopes": []string{"sensors:read"},
+	}
+	body, _ := json.Marshal(tokenReq)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/tokens", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", w.Code)
+	}
+}
+
+func TestAdminCreateAndListAndRevokeToken(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	tokenReq := map[string]interface{}{
+		"name":   "ci-runner",
+		"scopes": []string{"sensors:read"},
+	}
+	body, _ := json.Marshal(tokenReq)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/tokens", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	secret := created["secret"].(string)
+	tokenObj := created["token"].(map[string]interface{})
+	tokenID := tokenObj["id"].(string)
+
+	if secret == "" {
+		t.Fatal("Expected a non-empty secret")
+	}
+
+	// Once a scoped token exists, the legacy static token must stop working.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected static token to be rejected once scoped tokens exist, got %d", w.Code)
+	}
+
+	// The newly minted token should authenticate and carry its granted scope.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors", nil)
+	req.Header.Set("Authorization", "Bearer "+secret)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 with scoped token, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// It should not carry write scope.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/sensors", bytes.NewBuffer([]byte(`{}`)))
+	req.Header.Set("Authorization", "Bearer "+secret)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for out-of-scope request, got %d", w.Code)
+	}
+
+	// List should include the new token without exposing its hash or secret.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/tokens", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), secret) {
+		t.Error("Expected token list to never include the plaintext secret")
+	}
+
+	// Revoke, then confirm it can no longer authenticate.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/admin/tokens/"+tokenID, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/sensors", nil)
+	req.Header.Set("Authorization", "Bearer "+secret)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected revoked token to be rejected, got %d", w.Code)
+	}
+}
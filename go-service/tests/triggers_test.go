@@ -0,0 +1,325 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func createTestSensor(t *testing.T, router http.Handler) string {
+	t.Helper()
+
+	newSensor := map[string]interface{}{
+		"name":     "Trigger Test Sensor",
+		"type":     "temperature",
+		"location": "test_room",
+		"value":    10.0,
+		"unit":     "celsius",
+		"status":   "active",
+	}
+	body, _ := json.Marshal(newSensor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create sensor: %d: %s", w.Code, w.Body.String())
+	}
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	return created["id"].(string)
+}
+
+func createTestTrigger(t *testing.T, router http.Handler, sensorID, condition, webhookURL, secret string) map[string]interface{} {
+	t.Helper()
+
+	newTrigger := map[string]interface{}{
+		"condition":   condition,
+		"webhook_url": webhookURL,
+		"secret":      secret,
+	}
+	body, _ := json.Marshal(newTrigger)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/triggers", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create trigger: %d: %s", w.Code, w.Body.String())
+	}
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	return created
+}
+
+func TestCreateTriggerRejectsInvalidCondition(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensor(t, router)
+
+	newTrigger := map[string]interface{}{
+		"condition":   "value ~~ 80",
+		"webhook_url": "https://example.com/hook",
+		"secret":      "super-secret",
+	}
+	body, _ := json.Marshal(newTrigger)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/"+sensorID+"/triggers", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateTriggerForNonexistentSensor(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	newTrigger := map[string]interface{}{
+		"condition":   "value > 80",
+		"webhook_url": "https://example.com/hook",
+		"secret":      "super-secret",
+	}
+	body, _ := json.Marshal(newTrigger)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sensors/does-not-exist/triggers", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
+
+func TestListTriggersForSensor(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	sensorID := createTestSensor(t, router)
+	createTestTrigger(t, router, sensorID, "value > 80", "https://example.com/hook", "super-secret")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/sensors/"+sensorID+"/triggers", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+
+	var list map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if list["count"].(float64) != 1 {
+		t.Errorf("Expected 1 trigger, got %v", list["count"])
+	}
+}
+
+// webhookSink records every request it receives, verifying its HMAC
+// signature against secret before acknowledging.
+type webhookSink struct {
+	server *httptest.Server
+	secret string
+
+	mu       sync.Mutex
+	requests []webhookRequest
+}
+
+type webhookRequest struct {
+	body      []byte
+	signature string
+	valid     bool
+}
+
+func newWebhookSink(secret string, status int) *webhookSink {
+	sink := &webhookSink{secret: secret}
+	sink.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature := r.Header.Get("X-Signature")
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := hex.EncodeToString(mac.Sum(nil))
+
+		sink.mu.Lock()
+		sink.requests = append(sink.requests, webhookRequest{
+			body:      body,
+			signature: signature,
+			valid:     hmac.Equal([]byte(signature), []byte(expected)),
+		})
+		sink.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	return sink
+}
+
+func (s *webhookSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func (s *webhookSink) last() webhookRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func (s *webhookSink) close() {
+	s.server.Close()
+}
+
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("condition not met within %s", timeout)
+}
+
+func TestTriggerFiresSignedWebhookOnMatchingUpdate(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	secret := "super-secret-webhook-key"
+	sink := newWebhookSink(secret, http.StatusOK)
+	defer sink.close()
+
+	sensorID := createTestSensor(t, router)
+	createTestTrigger(t, router, sensorID, "value > 50", sink.server.URL, secret)
+
+	update := map[string]interface{}{"value": 99.0}
+	body, _ := json.Marshal(update)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/sensors/"+sensorID, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to update sensor: %d: %s", w.Code, w.Body.String())
+	}
+
+	waitFor(t, 2*time.Second, func() bool { return sink.count() > 0 })
+
+	last := sink.last()
+	if !last.valid {
+		t.Errorf("Expected a valid HMAC signature, got signature %q for body %s", last.signature, last.body)
+	}
+}
+
+func TestTriggerDoesNotFireOnNonMatchingUpdate(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	secret := "super-secret-webhook-key"
+	sink := newWebhookSink(secret, http.StatusOK)
+	defer sink.close()
+
+	sensorID := createTestSensor(t, router)
+	createTestTrigger(t, router, sensorID, "value > 50", sink.server.URL, secret)
+
+	update := map[string]interface{}{"value": 5.0}
+	body, _ := json.Marshal(update)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/sensors/"+sensorID, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to update sensor: %d: %s", w.Code, w.Body.String())
+	}
+
+	// Give the evaluator a chance to run before asserting nothing fired.
+	time.Sleep(100 * time.Millisecond)
+
+	if sink.count() != 0 {
+		t.Errorf("Expected no webhook deliveries, got %d", sink.count())
+	}
+}
+
+func TestTriggerDeadLettersAfterExhaustingRetriesAndReplays(t *testing.T) {
+	router, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	secret := "super-secret-webhook-key"
+	sink := newWebhookSink(secret, http.StatusInternalServerError)
+
+	sensorID := createTestSensor(t, router)
+	created := createTestTrigger(t, router, sensorID, "value > 50", sink.server.URL, secret)
+	triggerID := created["id"].(string)
+
+	update := map[string]interface{}{"value": 99.0}
+	body, _ := json.Marshal(update)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/sensors/"+sensorID, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to update sensor: %d: %s", w.Code, w.Body.String())
+	}
+
+	// The sink always fails, so the dispatcher should retry until it
+	// exhausts its attempts and dead-letters the delivery. The default
+	// test dispatcher caps attempts at 5 with backoff starting at ~1s, so
+	// this can take a few seconds.
+	waitFor(t, 15*time.Second, func() bool { return sink.count() >= 5 })
+	sink.close()
+
+	// Replaying should re-enqueue the dead-lettered delivery against a new
+	// sink that succeeds.
+	okSink := newWebhookSink(secret, http.StatusOK)
+	defer okSink.close()
+
+	// Point the trigger at a route we don't otherwise exercise: replay
+	// dispatches using the trigger's persisted webhook_url/secret, so we
+	// can only observe success by re-registering with the same URL. Since
+	// the in-memory trigger row can't be edited here, assert the replay
+	// endpoint reports what it attempted instead.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/triggers/"+triggerID+"/replay", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var replayResult map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &replayResult)
+	if replayResult["replayed"].(float64) < 1 {
+		t.Errorf("Expected at least 1 replayed delivery, got %v", replayResult["replayed"])
+	}
+}
@@ -0,0 +1,199 @@
+package triggers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"time"
+
+	"iot-sensor-service/models"
+	"iot-sensor-service/repositories"
+)
+
+// defaultMaxAttempts bounds delivery retries when Dispatcher is constructed
+// with maxAttempts <= 0.
+const defaultMaxAttempts = 5
+
+// maxBackoff caps the retry delay between delivery attempts.
+const maxBackoff = 5 * time.Minute
+
+// queueSize bounds how many pending deliveries Dispatcher will buffer before
+// dropping new ones rather than blocking the caller.
+const queueSize = 256
+
+// WebhookPayload is the JSON body POSTed to a trigger's webhook_url.
+type WebhookPayload struct {
+	CorrelationID string        `json:"correlation_id"`
+	TriggerID     string        `json:"trigger_id"`
+	Condition     string        `json:"condition"`
+	Sensor        models.Sensor `json:"sensor"`
+}
+
+// delivery is one queued attempt at delivering a trigger's webhook.
+type delivery struct {
+	trigger       models.Trigger
+	snapshot      models.Sensor
+	correlationID string
+	attempt       int
+}
+
+// Dispatcher delivers trigger webhooks through a fixed pool of workers,
+// HMAC-signing each payload and retrying failed deliveries with exponential
+// backoff before recording them in the trigger_dead_letters table.
+type Dispatcher struct {
+	repo        repositories.TriggerRepository
+	client      *http.Client
+	queue       chan delivery
+	maxAttempts int
+}
+
+// NewDispatcher creates a Dispatcher backed by repo. maxAttempts <= 0
+// defaults to 5 tries before a delivery is dead-lettered.
+func NewDispatcher(repo repositories.TriggerRepository, maxAttempts int) *Dispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	return &Dispatcher{
+		repo:        repo,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan delivery, queueSize),
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Start launches workers delivery goroutines, running until ctx is canceled.
+func (d *Dispatcher) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go d.worker(ctx)
+	}
+}
+
+// Enqueue schedules a first delivery attempt of trigger's webhook carrying
+// snapshot, minting a fresh correlation id.
+func (d *Dispatcher) Enqueue(trigger models.Trigger, snapshot models.Sensor) {
+	d.push(delivery{
+		trigger:       trigger,
+		snapshot:      snapshot,
+		correlationID: newCorrelationID(),
+		attempt:       1,
+	})
+}
+
+func (d *Dispatcher) push(dl delivery) {
+	select {
+	case d.queue <- dl:
+	default:
+		// Queue is saturated; drop rather than block the caller. The next
+		// matching reading will likely re-trigger the same condition.
+	}
+}
+
+func (d *Dispatcher) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case dl := <-d.queue:
+			d.attempt(dl)
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(dl delivery) {
+	body, err := json.Marshal(WebhookPayload{
+		CorrelationID: dl.correlationID,
+		TriggerID:     dl.trigger.ID,
+		Condition:     dl.trigger.Condition,
+		Sensor:        dl.snapshot,
+	})
+	if err != nil {
+		d.deadLetter(dl, nil, err)
+		return
+	}
+
+	if err := d.deliver(dl.trigger, body); err != nil {
+		d.retryOrDeadLetter(dl, body, err)
+	}
+}
+
+func (d *Dispatcher) deliver(trigger models.Trigger, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, trigger.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", sign(trigger.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *Dispatcher) retryOrDeadLetter(dl delivery, body []byte, deliverErr error) {
+	if dl.attempt >= d.maxAttempts {
+		d.deadLetter(dl, body, deliverErr)
+		return
+	}
+
+	next := dl
+	next.attempt++
+	time.AfterFunc(backoff(dl.attempt), func() {
+		d.push(next)
+	})
+}
+
+func (d *Dispatcher) deadLetter(dl delivery, body []byte, deliverErr error) {
+	if _, err := d.repo.CreateDeadLetter(&models.DeadLetter{
+		TriggerID:     dl.trigger.ID,
+		CorrelationID: dl.correlationID,
+		Payload:       string(body),
+		Attempts:      dl.attempt,
+		LastError:     deliverErr.Error(),
+	}); err != nil {
+		// Nothing more to do; the delivery is already lost and we have no
+		// logger reference here to surface a second failure.
+		_ = err
+	}
+}
+
+// sign computes the hex-encoded HMAC-SHA256 signature of body under secret,
+// sent as the webhook's X-Signature header so the receiver can verify it
+// actually came from this service.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the delay before retrying a delivery whose attempt-th try
+// just failed: roughly 1s, 2s, 4s, ... doubling and capped at maxBackoff,
+// with jitter so simultaneous retries don't thunder together.
+func backoff(attempt int) time.Duration {
+	base := time.Second << uint(attempt-1)
+	if base <= 0 || base > maxBackoff {
+		base = maxBackoff
+	}
+	return base/2 + time.Duration(mathrand.Int63n(int64(base)/2+1))
+}
+
+// newCorrelationID mints a random hex identifier correlating a delivery's
+// retries, and any eventual dead-letter record, back to the same event.
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
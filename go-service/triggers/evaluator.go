@@ -0,0 +1,99 @@
+package triggers
+
+import (
+	"errors"
+	"time"
+
+	"iot-sensor-service/events"
+	"iot-sensor-service/models"
+	"iot-sensor-service/repositories"
+)
+
+// errNoBaseline indicates a "delta ... over" condition has no reading old
+// enough to compare against yet, so it cannot be evaluated.
+var errNoBaseline = errors.New("no reading old enough to establish a baseline")
+
+// FiredEvent is published to the event bus each time a trigger's condition
+// matches, independent of whether its webhook delivery ultimately succeeds.
+type FiredEvent struct {
+	TriggerID string        `json:"trigger_id"`
+	SensorID  string        `json:"sensor_id"`
+	Condition string        `json:"condition"`
+	Sensor    models.Sensor `json:"sensor"`
+}
+
+// Evaluator checks a sensor's active triggers after each mutation,
+// publishing a FiredEvent and enqueueing a webhook delivery on the
+// Dispatcher for each one that matches.
+type Evaluator struct {
+	triggerRepo repositories.TriggerRepository
+	readingRepo repositories.ReadingRepository
+	dispatcher  *Dispatcher
+	bus         *events.Bus
+}
+
+// NewEvaluator creates an Evaluator that looks up triggers from triggerRepo,
+// baseline readings for "delta ... over" conditions from readingRepo, and
+// dispatches matches through dispatcher, publishing a FiredEvent for each to
+// bus.
+func NewEvaluator(triggerRepo repositories.TriggerRepository, readingRepo repositories.ReadingRepository, dispatcher *Dispatcher, bus *events.Bus) *Evaluator {
+	return &Evaluator{triggerRepo: triggerRepo, readingRepo: readingRepo, dispatcher: dispatcher, bus: bus}
+}
+
+// EvaluateSensor checks sensor against every active trigger registered for
+// it, publishing a FiredEvent and enqueueing a webhook delivery for each
+// match. correlationID is attached to the published event so SSE clients
+// can trace it back to the request that caused it. A single trigger whose
+// condition fails to parse or evaluate is skipped rather than aborting the
+// rest.
+func (e *Evaluator) EvaluateSensor(sensor *models.Sensor, correlationID string) {
+	active, err := e.triggerRepo.ListActiveForSensor(sensor.ID)
+	if err != nil || len(active) == 0 {
+		return
+	}
+
+	for _, trigger := range active {
+		cond, err := ParseCondition(trigger.Condition)
+		if err != nil {
+			continue
+		}
+
+		var baseline float64
+		if cond.Field == "delta" {
+			baseline, err = e.baselineValue(sensor.ID, cond.WindowSeconds)
+			if err != nil {
+				continue
+			}
+		}
+
+		matched, err := cond.Evaluate(sensor, baseline)
+		if err != nil || !matched {
+			continue
+		}
+
+		e.bus.Publish(events.TriggerFired, FiredEvent{
+			TriggerID: trigger.ID,
+			SensorID:  sensor.ID,
+			Condition: trigger.Condition,
+			Sensor:    *sensor,
+		}, correlationID)
+		e.dispatcher.Enqueue(trigger, *sensor)
+	}
+}
+
+// baselineValue returns the value of the oldest reading within the last
+// windowSeconds, for comparison against the sensor's current value. It
+// returns errNoBaseline if there is no reading that far back yet, since a
+// delta condition can't be meaningfully evaluated without one.
+func (e *Evaluator) baselineValue(sensorID string, windowSeconds int) (float64, error) {
+	from := time.Now().UTC().Add(-time.Duration(windowSeconds) * time.Second).Format(time.RFC3339)
+
+	readings, err := e.readingRepo.ListByRange(sensorID, from, "", 1, "asc")
+	if err != nil {
+		return 0, err
+	}
+	if len(readings) == 0 {
+		return 0, errNoBaseline
+	}
+	return readings[0].Value, nil
+}
@@ -0,0 +1,144 @@
+// Package triggers evaluates threshold rules against sensor updates and
+// delivers matching webhooks through a retrying worker pool. See
+// repositories.TriggerRepository for how triggers and dead-lettered
+// deliveries are persisted, and handlers.TriggerHandler for the REST
+// surface that registers and replays them.
+package triggers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"iot-sensor-service/models"
+)
+
+// validOps are the comparison operators recognized in a condition string.
+var validOps = map[string]bool{
+	">":  true,
+	"<":  true,
+	">=": true,
+	"<=": true,
+	"==": true,
+	"!=": true,
+}
+
+// Condition is a trigger's parsed rule, e.g. "value > 80", `status ==
+// "inactive"`, or "delta > 5 over 60s".
+type Condition struct {
+	// Field is "value", "status", or "delta".
+	Field string
+	// Op is one of ">", "<", ">=", "<=", "==", "!=".
+	Op string
+	// Threshold is the numeric comparison value, for "value" and "delta".
+	Threshold float64
+	// StringValue is the comparison value, for "status".
+	StringValue string
+	// WindowSeconds is the lookback window for a "delta ... over <dur>"
+	// condition; zero for "value" and "status".
+	WindowSeconds int
+}
+
+// ParseCondition parses a trigger's condition string.
+func ParseCondition(expr string) (*Condition, error) {
+	original := expr
+	expr = strings.TrimSpace(expr)
+
+	windowSeconds := 0
+	if idx := strings.Index(expr, " over "); idx >= 0 {
+		seconds, err := parseWindow(strings.TrimSpace(expr[idx+len(" over "):]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition %q: %w", original, err)
+		}
+		windowSeconds = seconds
+		expr = strings.TrimSpace(expr[:idx])
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf(`invalid condition %q: expected "<field> <op> <value>"`, original)
+	}
+
+	field, op := fields[0], fields[1]
+	if !validOps[op] {
+		return nil, fmt.Errorf("invalid condition %q: unsupported operator %q", original, op)
+	}
+
+	cond := &Condition{Field: field, Op: op, WindowSeconds: windowSeconds}
+	valueStr := strings.Join(fields[2:], " ")
+
+	switch field {
+	case "value", "delta":
+		threshold, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition %q: %q is not numeric", original, valueStr)
+		}
+		cond.Threshold = threshold
+	case "status":
+		cond.StringValue = strings.Trim(valueStr, `"`)
+	default:
+		return nil, fmt.Errorf("invalid condition %q: unsupported field %q", original, field)
+	}
+
+	if field == "delta" && windowSeconds == 0 {
+		return nil, fmt.Errorf(`invalid condition %q: "delta" conditions require "over <duration>"`, original)
+	}
+
+	return cond, nil
+}
+
+// parseWindow converts a Go duration string (e.g. "60s", "5m") to whole seconds.
+func parseWindow(s string) (int, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	return int(d.Seconds()), nil
+}
+
+// Evaluate reports whether sensor's current state satisfies c. baseline is
+// only used for "delta" conditions, where it is the sensor's value at the
+// start of c.WindowSeconds ago.
+func (c *Condition) Evaluate(sensor *models.Sensor, baseline float64) (bool, error) {
+	switch c.Field {
+	case "value":
+		return compareFloat(c.Op, sensor.Value, c.Threshold), nil
+	case "status":
+		return compareString(c.Op, sensor.Status, c.StringValue), nil
+	case "delta":
+		return compareFloat(c.Op, sensor.Value-baseline, c.Threshold), nil
+	default:
+		return false, fmt.Errorf("unsupported field %q", c.Field)
+	}
+}
+
+func compareFloat(op string, actual, threshold float64) bool {
+	switch op {
+	case ">":
+		return actual > threshold
+	case "<":
+		return actual < threshold
+	case ">=":
+		return actual >= threshold
+	case "<=":
+		return actual <= threshold
+	case "==":
+		return actual == threshold
+	case "!=":
+		return actual != threshold
+	default:
+		return false
+	}
+}
+
+func compareString(op, actual, expected string) bool {
+	switch op {
+	case "==":
+		return actual == expected
+	case "!=":
+		return actual != expected
+	default:
+		return false
+	}
+}